@@ -3,8 +3,9 @@ module github.com/mattiarossi/packer-builder-oracle-ocisurrogate
 go 1.13
 
 require (
+	github.com/go-ini/ini v1.25.4
 	github.com/hashicorp/hcl/v2 v2.4.0
 	github.com/hashicorp/packer v1.5.5
-	github.com/oracle/oci-go-sdk v19.0.0+incompatible
+	github.com/oracle/oci-go-sdk/v65 v65.55.0
 	github.com/zclconf/go-cty v1.4.0
 )
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"os"
+
 	"github.com/hashicorp/packer/packer/plugin"
 	"github.com/mattiarossi/packer-builder-oracle-ocisurrogate/pkg/ocisurrogate"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "sweep" {
+		os.Exit(ocisurrogate.Sweep(os.Args[2:], os.Stdout))
+	}
+
 	server, err := plugin.Server()
 	if err != nil {
 		panic(err)
@@ -3,28 +3,47 @@ package ocisurrogate
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/oracle/oci-go-sdk/core"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 // Artifact is an artifact implementation that contains a built Custom Image.
 type Artifact struct {
 	Image  core.Image
 	Region string
-	driver Driver
+	// ExportURL is the URL of the image exported to Object Storage. It is
+	// also available as the export_url state and the ExportURL generated
+	// data, as it is not a local file.
+	ExportURL string
+	// Copies of the image in other regions or compartments.
+	Copies []ImageCopy
+	// MovedToCompartment is the compartment the image was moved to, if any.
+	MovedToCompartment string
+	// PublishURL is the URL of the pre-authenticated request publishing the
+	// exported image, if any.
+	PublishURL string
+	driver     Driver
 
 	// StateData should store data such as GeneratedData
 	// to be shared with post-processors
 	StateData map[string]interface{}
 }
 
+// ImageCopy is a copy of the image imported from its export.
+type ImageCopy struct {
+	Region        string
+	CompartmentID string
+	ID            string
+}
+
 // BuilderId uniquely identifies the builder.
 func (a *Artifact) BuilderId() string {
 	return BuilderId
 }
 
-// Files lists the files associated with an artifact. We don't have any files
-// as the custom image is stored server side.
+// Files returns no files as custom images are stored server side.
 func (a *Artifact) Files() []string {
 	return nil
 }
@@ -40,10 +59,27 @@ func (a *Artifact) String() string {
 		displayName = *a.Image.DisplayName
 	}
 
-	return fmt.Sprintf(
+	s := fmt.Sprintf(
 		"An image was created: '%v' (OCID: %v) in region '%v'",
 		displayName, *a.Image.Id, a.Region,
 	)
+	if a.ExportURL != "" {
+		s += fmt.Sprintf(", exported to %s", a.ExportURL)
+	}
+	if a.PublishURL != "" {
+		s += fmt.Sprintf(", published at %s", a.PublishURL)
+	}
+	if a.MovedToCompartment != "" {
+		s += fmt.Sprintf(", moved to compartment %s", a.MovedToCompartment)
+	}
+	if len(a.Copies) > 0 {
+		copies := make([]string, 0, len(a.Copies))
+		for _, c := range a.Copies {
+			copies = append(copies, fmt.Sprintf("%s (%s)", c.ID, c.Region))
+		}
+		s += fmt.Sprintf(", copied to %s", strings.Join(copies, ", "))
+	}
+	return s
 }
 
 // State ...
@@ -51,7 +87,20 @@ func (a *Artifact) State(name string) interface{} {
 	return a.StateData[name]
 }
 
-// Destroy deletes the custom image associated with the artifact.
+// Destroy deletes the custom image associated with the artifact and its
+// copies in other regions.
 func (a *Artifact) Destroy() error {
-	return a.driver.DeleteImage(context.TODO(), *a.Image.Id)
+	var errs *packer.MultiError
+	for _, c := range a.Copies {
+		if err := a.driver.DeleteRegionalImage(context.TODO(), c.Region, c.ID); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error deleting image copy %s in %s: %s", c.ID, c.Region, err))
+		}
+	}
+	if err := a.driver.DeleteImage(context.TODO(), *a.Image.Id); err != nil {
+		errs = packer.MultiErrorAppend(errs, err)
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
 }
@@ -1,9 +1,11 @@
 package ocisurrogate
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 func TestArtifactImpl(t *testing.T) {
@@ -13,3 +15,44 @@ func TestArtifactImpl(t *testing.T) {
 		t.Fatalf("Artifact should be artifact")
 	}
 }
+
+func TestArtifactFiles(t *testing.T) {
+	a := &Artifact{}
+	if files := a.Files(); files != nil {
+		t.Fatalf("should have no files, got %v", files)
+	}
+
+	a.ExportURL = "https://objectstorage.us-ashburn-1.oraclecloud.com/n/ns/b/images/o/image.qcow2"
+	if files := a.Files(); files != nil {
+		t.Fatalf("should not list the exported object as a local file, got %v", files)
+	}
+}
+
+func TestArtifactCopies(t *testing.T) {
+	id := "ocid1.image"
+	driver := &driverMock{}
+	a := &Artifact{
+		Image:  core.Image{Id: &id},
+		Region: "us-ashburn-1",
+		Copies: []ImageCopy{
+			{Region: "eu-frankfurt-1", CompartmentID: "ocid1.compartment", ID: "ocid1.image.oc1.fra"},
+			{Region: "us-phoenix-1", CompartmentID: "ocid1.compartment", ID: "ocid1.image.oc1.phx"},
+		},
+		driver: driver,
+	}
+
+	expected := "copied to ocid1.image.oc1.fra (eu-frankfurt-1), ocid1.image.oc1.phx (us-phoenix-1)"
+	if s := a.String(); !strings.HasSuffix(s, expected) {
+		t.Errorf("bad artifact string: %s", s)
+	}
+
+	if err := a.Destroy(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Join(driver.DeleteRegionalImageIDs, " ") != "ocid1.image.oc1.fra ocid1.image.oc1.phx" {
+		t.Errorf("should delete every copy, got %v", driver.DeleteRegionalImageIDs)
+	}
+	if driver.DeleteImageID != id {
+		t.Errorf("should delete the image, got %q", driver.DeleteImageID)
+	}
+}
@@ -0,0 +1,187 @@
+//go:generate mapstructure-to-hcl2 -type Bootloader
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Bootloader describes how GRUB is configured and installed on the
+// surrogate. It is applied inside the chroot after provisioning, and
+// requires use_chroot.
+type Bootloader struct {
+	// Firmware the image boots with, either uefi or bios. The bootloader is
+	// left untouched when empty.
+	Firmware string `mapstructure:"firmware"`
+	// KernelArgs are appended to the kernel command line.
+	KernelArgs []string `mapstructure:"kernel_args"`
+	// SkipDefaultKernelArgs leaves out the arguments OCI platform images
+	// boot with (serial console, iSCSI boot volume and network settings).
+	SkipDefaultKernelArgs bool `mapstructure:"skip_default_kernel_args"`
+	// Root is the root= kernel argument. Defaults to the root dataset of
+	// zfs_root when used.
+	Root string `mapstructure:"root"`
+	// PreloadModules are the GRUB modules set in GRUB_PRELOAD_MODULES.
+	// Defaults to part_gpt and zfs when zfs_root is used.
+	PreloadModules []string `mapstructure:"preload_modules"`
+	// GrubConfig is the path of grub.cfg inside the surrogate. Defaults to
+	// /boot/efi/EFI/redhat/grub.cfg for uefi and /boot/grub2/grub.cfg for
+	// bios.
+	GrubConfig string `mapstructure:"grub_config"`
+	// EFIDirectory is the mount point of the EFI system partition inside
+	// the surrogate, used with uefi. Defaults to /boot/efi.
+	EFIDirectory string `mapstructure:"efi_directory"`
+	// GrubRoot replaces the `set root` device of the generated grub.cfg,
+	// e.g. hd0,gpt2, for root filesystems grub2-probe can't resolve.
+	GrubRoot string `mapstructure:"grub_root"`
+	// InstallDevice is the device GRUB is installed to. Defaults to the
+	// surrogate volume.
+	InstallDevice string `mapstructure:"install_device"`
+	// RegenerateInitramfs rebuilds every initramfs of the surrogate with
+	// dracut.
+	RegenerateInitramfs bool `mapstructure:"regenerate_initramfs"`
+}
+
+// ociKernelArgs are the kernel arguments of the OCI Oracle Linux platform
+// images, needed to boot from an iSCSI attached boot volume with the serial
+// console enabled.
+var ociKernelArgs = []string{
+	"crashkernel=auto",
+	"LANG=en_US.UTF-8",
+	"console=tty0",
+	"console=ttyS0,9600",
+	"rd.luks=0",
+	"rd.lvm=0",
+	"rd.md=0",
+	"rd.dm=0",
+	"netroot=iscsi:169.254.0.2:::1:iqn.2015-02.oracle.boot:uefi",
+	"iscsi_param=node.session.timeo.replacement_timeout=6000",
+	"net.ifnames=1",
+	"nvme_core.shutdown_timeout=10",
+	"ipmi_si.tryacpi=0",
+	"ipmi_si.trydmi=0",
+	"ipmi_si.trydefaults=0",
+	"libiscsi.debug_libiscsi_eh=1",
+	"network-config=e2NvbmZpZzogZGlzYWJsZWR9Cg==",
+	"loglevel=4",
+}
+
+func (b *Bootloader) Prepare(zfs *ZFSRoot, useChroot bool) []error {
+	var errs []error
+
+	if b.Firmware == "" {
+		return errs
+	}
+
+	switch b.Firmware {
+	case "uefi":
+		if b.GrubConfig == "" {
+			b.GrubConfig = "/boot/efi/EFI/redhat/grub.cfg"
+		}
+		if b.EFIDirectory == "" {
+			b.EFIDirectory = "/boot/efi"
+		}
+		if !path.IsAbs(b.EFIDirectory) {
+			errs = append(errs, fmt.Errorf("bootloader: efi_directory must be an absolute path, got %q", b.EFIDirectory))
+		}
+	case "bios":
+		if b.GrubConfig == "" {
+			b.GrubConfig = "/boot/grub2/grub.cfg"
+		}
+	default:
+		errs = append(errs, fmt.Errorf("bootloader: firmware must be one of uefi or bios, got %q", b.Firmware))
+	}
+
+	if !useChroot {
+		errs = append(errs, fmt.Errorf("bootloader: use_chroot must be enabled"))
+	}
+	if b.GrubConfig != "" && !path.IsAbs(b.GrubConfig) {
+		errs = append(errs, fmt.Errorf("bootloader: grub_config must be an absolute path, got %q", b.GrubConfig))
+	}
+
+	if zfs.enabled {
+		if b.Root == "" {
+			b.Root = "ZFS=" + zfs.rootDataset()
+		}
+		if b.PreloadModules == nil {
+			b.PreloadModules = []string{"part_gpt", "zfs"}
+		}
+	}
+
+	if b.InstallDevice != "" && !path.IsAbs(b.InstallDevice) {
+		errs = append(errs, fmt.Errorf("bootloader: install_device must be an absolute path, got %q", b.InstallDevice))
+	}
+
+	return errs
+}
+
+// kernelCmdline returns the GRUB_CMDLINE_LINUX value of the surrogate.
+func (b *Bootloader) kernelCmdline() string {
+	var args []string
+	if !b.SkipDefaultKernelArgs {
+		args = append(args, ociKernelArgs...)
+	}
+	args = append(args, b.KernelArgs...)
+	if strings.HasPrefix(b.Root, "ZFS=") {
+		args = append(args, "boot=zfs")
+	}
+	if b.Root != "" {
+		args = append(args, "root="+b.Root)
+	}
+	return strings.Join(args, " ")
+}
+
+// grubInstallCommand returns the command installing GRUB to device. With
+// uefi, the NVRAM of the builder instance is left untouched.
+func (b *Bootloader) grubInstallCommand(device string) string {
+	if b.Firmware == "uefi" {
+		return fmt.Sprintf("grub2-install --target=x86_64-efi --efi-directory=%s --no-nvram -d /usr/lib/grub/x86_64-efi %s",
+			shellQuote(b.EFIDirectory), shellQuote(device))
+	}
+	return "grub2-install --target=i386-pc " + shellQuote(device)
+}
+
+// updateGrubDefaults sets the kernel command line and preloaded modules in
+// the content of /etc/default/grub.
+func (b *Bootloader) updateGrubDefaults(content string) string {
+	content = setShellVariable(content, "GRUB_CMDLINE_LINUX", b.kernelCmdline())
+	if len(b.PreloadModules) > 0 {
+		content = setShellVariable(content, "GRUB_PRELOAD_MODULES", strings.Join(b.PreloadModules, " "))
+	}
+	return content
+}
+
+var (
+	grubSetRootRe  = regexp.MustCompile(`(?m)^(\s*)set root=.*$`)
+	grubNullRootRe = regexp.MustCompile(`[ \t]*root=\S*\(null\)\S*`)
+)
+
+// patchGrubConfig fixes the grub.cfg generated by grub2-mkconfig for root
+// filesystems grub2-probe can't resolve: root= arguments pointing to a
+// "(null)" device are dropped and, if set, `set root` uses GrubRoot.
+func (b *Bootloader) patchGrubConfig(content string) string {
+	content = grubNullRootRe.ReplaceAllString(content, "")
+	if b.GrubRoot != "" {
+		// GRUB quotes words like /bin/sh does.
+		setRoot := "set root=" + shellQuote(b.GrubRoot)
+		content = grubSetRootRe.ReplaceAllString(content, "${1}"+strings.Replace(setRoot, "$", "$$", -1))
+	}
+	return content
+}
+
+// setShellVariable sets name to value in content, a file of shell variable
+// assignments, replacing an existing assignment or appending a new one.
+func setShellVariable(content string, name string, value string) string {
+	line := name + "=" + shellQuote(value)
+	re := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(name) + `=.*$`)
+	if re.MatchString(content) {
+		return re.ReplaceAllLiteralString(content, line)
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + line + "\n"
+}
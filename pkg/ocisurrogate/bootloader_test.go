@@ -0,0 +1,126 @@
+package ocisurrogate
+
+import (
+	"strings"
+	"testing"
+)
+
+func testZFSBootloader(t *testing.T) Bootloader {
+	layout := testZFSLayout()
+	layout.Prepare()
+	zfs := ZFSRoot{}
+	zfs.Prepare(&layout)
+
+	b := Bootloader{Firmware: "uefi", GrubRoot: "hd0,gpt2"}
+	if errs := b.Prepare(&zfs, true); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	return b
+}
+
+func TestBootloaderPrepare(t *testing.T) {
+	b := testZFSBootloader(t)
+
+	if b.GrubConfig != "/boot/efi/EFI/redhat/grub.cfg" {
+		t.Errorf("bad grub_config default: %s", b.GrubConfig)
+	}
+	if b.Root != "ZFS=rpool/ROOT/default" {
+		t.Errorf("bad root default: %s", b.Root)
+	}
+	if strings.Join(b.PreloadModules, " ") != "part_gpt zfs" {
+		t.Errorf("bad preload_modules default: %v", b.PreloadModules)
+	}
+	if b.EFIDirectory != "/boot/efi" {
+		t.Errorf("bad efi_directory default: %s", b.EFIDirectory)
+	}
+	if b.InstallDevice != "" {
+		t.Errorf("install_device should default to the surrogate volume, got %s", b.InstallDevice)
+	}
+}
+
+func TestBootloaderPrepare_Errors(t *testing.T) {
+	cases := map[string]Bootloader{
+		"firmware":       {Firmware: "coreboot"},
+		"grub_config":    {Firmware: "bios", GrubConfig: "grub.cfg"},
+		"install_device": {Firmware: "bios", InstallDevice: "sdb"},
+	}
+	for name, b := range cases {
+		errs := b.Prepare(&ZFSRoot{}, true)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), name) {
+			t.Errorf("%s: expected a single error about %s, got %v", name, name, errs)
+		}
+	}
+
+	b := Bootloader{Firmware: "bios"}
+	if errs := b.Prepare(&ZFSRoot{}, false); len(errs) != 1 {
+		t.Errorf("expected an error without use_chroot, got %v", errs)
+	}
+}
+
+func TestBootloaderGrubInstallCommand(t *testing.T) {
+	b := testZFSBootloader(t)
+	expected := "grub2-install --target=x86_64-efi --efi-directory='/boot/efi' --no-nvram -d /usr/lib/grub/x86_64-efi '/dev/sdb'"
+	if command := b.grubInstallCommand("/dev/sdb"); command != expected {
+		t.Errorf("bad uefi command:\n got: %s\nwant: %s", command, expected)
+	}
+
+	b.Firmware = "bios"
+	if command, expected := b.grubInstallCommand("/dev/sdb"), "grub2-install --target=i386-pc '/dev/sdb'"; command != expected {
+		t.Errorf("bad bios command:\n got: %s\nwant: %s", command, expected)
+	}
+}
+
+func TestBootloaderKernelCmdline(t *testing.T) {
+	b := testZFSBootloader(t)
+	b.SkipDefaultKernelArgs = true
+	b.KernelArgs = []string{"console=ttyS0,115200"}
+
+	expected := "console=ttyS0,115200 boot=zfs root=ZFS=rpool/ROOT/default"
+	if cmdline := b.kernelCmdline(); cmdline != expected {
+		t.Errorf("bad kernel command line:\n got: %s\nwant: %s", cmdline, expected)
+	}
+
+	b.SkipDefaultKernelArgs = false
+	if cmdline := b.kernelCmdline(); !strings.HasPrefix(cmdline, "crashkernel=auto ") {
+		t.Errorf("expected default kernel args, got: %s", cmdline)
+	}
+}
+
+func TestBootloaderUpdateGrubDefaults(t *testing.T) {
+	b := Bootloader{Root: "/dev/sda3", SkipDefaultKernelArgs: true, PreloadModules: []string{"part_gpt"}}
+
+	content := "GRUB_TIMEOUT=5\nGRUB_CMDLINE_LINUX=\"rhgb quiet\"\nGRUB_DISABLE_RECOVERY=\"true\""
+	expected := "GRUB_TIMEOUT=5\nGRUB_CMDLINE_LINUX='root=/dev/sda3'\nGRUB_DISABLE_RECOVERY=\"true\"\nGRUB_PRELOAD_MODULES='part_gpt'\n"
+	if updated := b.updateGrubDefaults(content); updated != expected {
+		t.Errorf("bad /etc/default/grub:\n got: %q\nwant: %q", updated, expected)
+	}
+}
+
+func TestSetShellVariable(t *testing.T) {
+	expected := "GRUB_CMDLINE_LINUX='console=$tty '\"'\"'quiet'\"'\"''\n"
+	if content := setShellVariable("", "GRUB_CMDLINE_LINUX", "console=$tty 'quiet'"); content != expected {
+		t.Errorf("bad assignment:\n got: %q\nwant: %q", content, expected)
+	}
+}
+
+func TestBootloaderPatchGrubConfig(t *testing.T) {
+	b := testZFSBootloader(t)
+
+	content := "" +
+		"\tinsmod zfs\n" +
+		"\tset root='hd0,gpt3'\n" +
+		"\tlinuxefi /ROOT/default@/boot/vmlinuz root=ZFS=(null)/ROOT/default ro boot=zfs root=ZFS=rpool/ROOT/default\n"
+	expected := "" +
+		"\tinsmod zfs\n" +
+		"\tset root='hd0,gpt2'\n" +
+		"\tlinuxefi /ROOT/default@/boot/vmlinuz ro boot=zfs root=ZFS=rpool/ROOT/default\n"
+	if patched := b.patchGrubConfig(content); patched != expected {
+		t.Errorf("bad grub.cfg:\n got: %q\nwant: %q", patched, expected)
+	}
+
+	b.GrubRoot = "hd0,gpt2' $x"
+	expected = "\tset root='hd0,gpt2'\"'\"' $x'\n"
+	if patched := b.patchGrubConfig("\tset root='hd0,gpt3'\n"); patched != expected {
+		t.Errorf("bad quoting of grub_root:\n got: %q\nwant: %q", patched, expected)
+	}
+}
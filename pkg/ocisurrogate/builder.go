@@ -7,12 +7,14 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer/builder"
 	ocommon "github.com/hashicorp/packer/builder/oracle/common"
 	"github.com/hashicorp/packer/common"
 	"github.com/hashicorp/packer/helper/communicator"
 	"github.com/hashicorp/packer/helper/multistep"
 	"github.com/hashicorp/packer/packer"
-	"github.com/oracle/oci-go-sdk/core"
+	"github.com/hashicorp/packer/template/interpolate"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 // BuilderId uniquely identifies the builder
@@ -35,7 +37,27 @@ func (b *Builder) Prepare(raws ...interface{}) ([]string, []string, error) {
 		return nil, nil, err
 	}
 
-	return nil, nil, nil
+	generatedData := []string{
+		"Region",
+		"AvailabilityDomain",
+		"BuildUUID",
+		"SourceImageOCID",
+		"SourceImageName",
+		"InstanceOCID",
+		"InstanceIP",
+		"SurrogateVolumeOCID",
+		"SurrogateAttachmentOCID",
+		"SurrogateInstanceOCID",
+		"SurrogateDevice",
+		"SurrogateIscsiIqn",
+		"SurrogateIscsiIpv4",
+		"SurrogateIscsiPort",
+		"ExportURL",
+		"ExportPublishURL",
+		"PreviousImageOCID",
+	}
+
+	return generatedData, nil, nil
 }
 
 func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (packer.Artifact, error) {
@@ -44,12 +66,30 @@ func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (pack
 		return nil, err
 	}
 
+	region, err := b.config.configProvider.Region()
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedCommand := func(command string) (string, error) {
+		ictx := b.config.ctx
+		ictx.Data = &wrappedCommandTemplate{Command: command}
+		return interpolate.Render(b.config.CommandWrapper, &ictx)
+	}
+
 	// Populate the state bag
 	state := new(multistep.BasicStateBag)
 	state.Put("config", &b.config)
 	state.Put("driver", driver)
 	state.Put("hook", hook)
 	state.Put("ui", ui)
+	state.Put("wrappedCommand", common.CommandWrapper(wrappedCommand))
+	state.Put("region", region)
+
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("Region", region)
+	generatedData.Put("AvailabilityDomain", b.config.AvailabilityDomain)
+	generatedData.Put("BuildUUID", b.config.runTags[runTagBuildUUID])
 
 	// Build the steps
 	steps := []multistep.Step{
@@ -58,7 +98,11 @@ func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (pack
 			Comm:         &b.config.Comm,
 			DebugKeyPath: fmt.Sprintf("oci_%s.pem", b.config.PackerBuildName),
 		},
+		&stepSourceImage{},
+		&stepCreateSeedVolume{},
 		&stepCreateInstance{},
+		&stepAttachmentInfo{},
+		&stepCreateSurrogateVolumes{},
 		&stepInstanceInfo{},
 		&stepGetDefaultCredentials{
 			Debug:     b.config.PackerDebug,
@@ -70,12 +114,38 @@ func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (pack
 			Host:      communicator.CommHost(b.config.Comm.Host(), "instance_ip"),
 			SSHConfig: b.config.Comm.SSHConfigFunc(),
 		},
-		&common.StepProvision{},
+		&stepISCSILogin{},
+		&stepPartitionSurrogate{},
+		&stepCreateZpool{},
+		&stepMountSurrogate{},
+		&stepCopyRoot{},
+		&stepChrootMounts{},
+	}
+
+	if b.config.UseChroot {
+		steps = append(steps, &stepChrootProvision{})
+	} else {
+		steps = append(steps, &common.StepProvision{})
+	}
+
+	steps = append(steps,
+		&stepInstallBootloader{},
 		&common.StepCleanupTempKeys{
 			Comm: &b.config.Comm,
 		},
+		&stepEarlyCleanup{},
+		&stepDetachSurrogateVolumes{},
+		&stepCreateSurrogate{},
+		&stepAttachSurrogateVolumes{},
+		&stepVerifySurrogate{},
 		&stepImage{},
-	}
+		&stepImageFamily{},
+		&stepImageCapabilities{},
+		&stepExportImage{},
+		&stepCopyImage{},
+		&stepMoveImage{},
+		&stepPruneImages{},
+	)
 
 	// Run the steps
 	b.runner = common.NewRunnerWithPauseFn(steps, b.config.PackerConfig, ui, state)
@@ -86,11 +156,6 @@ func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (pack
 		return nil, rawErr.(error)
 	}
 
-	region, err := b.config.configProvider.Region()
-	if err != nil {
-		return nil, err
-	}
-
 	image, ok := state.GetOk("image")
 	if !ok {
 		return nil, err
@@ -103,6 +168,30 @@ func (b *Builder) Run(ctx context.Context, ui packer.Ui, hook packer.Hook) (pack
 		driver:    driver,
 		StateData: map[string]interface{}{"generated_data": state.Get("generated_data")},
 	}
+	if url, ok := state.GetOk("export_url"); ok {
+		artifact.ExportURL = url.(string)
+	}
+	if copies, ok := state.GetOk("image_copies"); ok {
+		artifact.Copies = copies.([]ImageCopy)
+	}
+	if compartment, ok := state.GetOk("image_compartment_id"); ok {
+		artifact.MovedToCompartment = compartment.(string)
+	}
+	if url, ok := state.GetOk("export_publish_url"); ok {
+		artifact.PublishURL = url.(string)
+	}
+	for _, key := range []string{
+		"image_compatible_shapes",
+		"image_capability_schema_id",
+		"export_url",
+		"export_publish_url",
+		"image_compartment_id",
+		"image_copies",
+	} {
+		if value, ok := state.GetOk(key); ok {
+			artifact.StateData[key] = value
+		}
+	}
 
 	return artifact, nil
 }
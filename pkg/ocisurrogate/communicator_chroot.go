@@ -0,0 +1,112 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+
+	"github.com/hashicorp/packer/common"
+	"github.com/hashicorp/packer/common/uuid"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// chrootCommunicator wraps the communicator of the builder instance so that
+// commands run, and files are transferred, inside the mounted surrogate
+// root. Files are staged in /tmp on the builder instance and moved in or out
+// of the chroot with the configured command_wrapper, since the connecting
+// user is usually not allowed to write there.
+type chrootCommunicator struct {
+	Comm       packer.Communicator
+	Chroot     string
+	CmdWrapper common.CommandWrapper
+}
+
+// newChrootCommunicator returns a chrootCommunicator for the surrogate
+// mounted on mount_path.
+func newChrootCommunicator(state multistep.StateBag) *chrootCommunicator {
+	return &chrootCommunicator{
+		Comm:       state.Get("communicator").(packer.Communicator),
+		Chroot:     state.Get("mount_path").(string),
+		CmdWrapper: state.Get("wrappedCommand").(common.CommandWrapper),
+	}
+}
+
+func (c *chrootCommunicator) Start(ctx context.Context, cmd *packer.RemoteCmd) error {
+	command, err := c.CmdWrapper(
+		fmt.Sprintf("chroot %s /bin/sh -c %s", shellQuote(c.Chroot), shellQuote(cmd.Command)))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Executing in chroot %s: %s", c.Chroot, cmd.Command)
+	cmd.Command = command
+	return c.Comm.Start(ctx, cmd)
+}
+
+func (c *chrootCommunicator) Upload(dst string, r io.Reader, fi *os.FileInfo) error {
+	tmp := c.stagingPath()
+	if err := c.Comm.Upload(tmp, r, fi); err != nil {
+		return err
+	}
+
+	dst = path.Join(c.Chroot, dst)
+	log.Printf("Uploading to chroot dir: %s", dst)
+	return c.run(fmt.Sprintf("cp %s %s; status=$?; rm -f %s; exit $status",
+		tmp, shellQuote(dst), tmp))
+}
+
+func (c *chrootCommunicator) UploadDir(dst string, src string, exclude []string) error {
+	tmp := c.stagingPath()
+	if err := c.Comm.UploadDir(tmp, src, exclude); err != nil {
+		return err
+	}
+
+	dst = path.Join(c.Chroot, dst)
+	log.Printf("Uploading directory '%s' to '%s'", src, dst)
+	return c.run(fmt.Sprintf("mkdir -p %s && cp -R %s/. %s; status=$?; rm -rf %s; exit $status",
+		shellQuote(dst), tmp, shellQuote(dst), tmp))
+}
+
+func (c *chrootCommunicator) Download(src string, w io.Writer) error {
+	tmp := c.stagingPath()
+	src = path.Join(c.Chroot, src)
+	log.Printf("Downloading from chroot dir: %s", src)
+	if err := c.run(fmt.Sprintf("cp %s %s && chmod a+r %s", shellQuote(src), tmp, tmp)); err != nil {
+		return err
+	}
+	defer c.run(fmt.Sprintf("rm -f %s", tmp))
+
+	return c.Comm.Download(tmp, w)
+}
+
+func (c *chrootCommunicator) DownloadDir(src string, dst string, exclude []string) error {
+	tmp := c.stagingPath()
+	src = path.Join(c.Chroot, src)
+	log.Printf("Downloading directory '%s' to '%s'", src, dst)
+	if err := c.run(fmt.Sprintf("cp -R %s %s && chmod -R a+rX %s", shellQuote(src), tmp, tmp)); err != nil {
+		return err
+	}
+	defer c.run(fmt.Sprintf("rm -rf %s", tmp))
+
+	return c.Comm.DownloadDir(tmp, dst, exclude)
+}
+
+// stagingPath returns a unique path on the builder instance, outside of the
+// chroot, used to stage transferred files.
+func (c *chrootCommunicator) stagingPath() string {
+	return "/tmp/packer-chroot-" + uuid.TimeOrderedUUID()
+}
+
+// run runs command on the builder instance, outside of the chroot.
+func (c *chrootCommunicator) run(command string) error {
+	command, err := c.CmdWrapper("/bin/sh -c " + shellQuote(command))
+	if err != nil {
+		return err
+	}
+	return runCommand(context.TODO(), c.Comm, command, ioutil.Discard)
+}
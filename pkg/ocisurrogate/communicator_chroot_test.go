@@ -0,0 +1,62 @@
+package ocisurrogate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/packer/packer"
+)
+
+func testChrootCommunicator() (*chrootCommunicator, *commandRecorder) {
+	recorder := new(commandRecorder)
+	return &chrootCommunicator{
+		Comm:   recorder,
+		Chroot: "/mnt",
+		CmdWrapper: func(command string) (string, error) {
+			return "sudo " + command, nil
+		},
+	}, recorder
+}
+
+func TestChrootCommunicator_ImplementsCommunicator(t *testing.T) {
+	var raw interface{}
+	raw = &chrootCommunicator{}
+	if _, ok := raw.(packer.Communicator); !ok {
+		t.Fatalf("chrootCommunicator should be a communicator")
+	}
+}
+
+func TestChrootCommunicator_Start(t *testing.T) {
+	comm, recorder := testChrootCommunicator()
+
+	cmd := &packer.RemoteCmd{Command: "echo 'hello'"}
+	if err := comm.Start(context.Background(), cmd); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cmd.Wait()
+
+	expected := `sudo chroot '/mnt' /bin/sh -c 'echo '"'"'hello'"'"''`
+	if len(recorder.Commands) != 1 || recorder.Commands[0] != expected {
+		t.Fatalf("bad command:\n got: %v\nwant: %s", recorder.Commands, expected)
+	}
+}
+
+func TestChrootCommunicator_Upload(t *testing.T) {
+	comm, recorder := testChrootCommunicator()
+
+	if err := comm.Upload("/etc/motd", strings.NewReader("hello"), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	staged := recorder.UploadPath
+	if !strings.HasPrefix(staged, "/tmp/packer-chroot-") {
+		t.Fatalf("should stage the upload in /tmp, got %s", staged)
+	}
+	if recorder.UploadData != "hello" {
+		t.Fatalf("bad upload data: %s", recorder.UploadData)
+	}
+	if len(recorder.Commands) != 1 || !strings.Contains(recorder.Commands[0], "cp "+staged+` '"'"'/mnt/etc/motd'"'"'`) {
+		t.Fatalf("should copy the staged file into the chroot, got %v", recorder.Commands)
+	}
+}
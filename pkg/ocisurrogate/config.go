@@ -14,12 +14,13 @@ import (
 	"strings"
 
 	"github.com/hashicorp/packer/common"
+	"github.com/hashicorp/packer/common/uuid"
 	"github.com/hashicorp/packer/helper/communicator"
 	"github.com/hashicorp/packer/helper/config"
 	"github.com/hashicorp/packer/packer"
 	"github.com/hashicorp/packer/template/interpolate"
-	ocicommon "github.com/oracle/oci-go-sdk/common"
-	ociauth "github.com/oracle/oci-go-sdk/common/auth"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	ociauth "github.com/oracle/oci-go-sdk/v65/common/auth"
 )
 
 type Config struct {
@@ -56,11 +57,40 @@ type Config struct {
 	CompartmentID      string `mapstructure:"compartment_ocid"`
 
 	// Image
-	BaseImageID         string `mapstructure:"base_image_ocid"`
-	BaseImageName       string `mapstructure:"base_image_name"`
-	Shape               string `mapstructure:"shape"`
-	ImageName           string `mapstructure:"image_name"`
-	BootVolumeSizeInGBs int64  `mapstructure:"bootvolumesize"`
+	// BaseImageID, BaseImageName or BaseImageFilter, Shape and
+	// BootVolumeSizeInGBs describe the builder (helper) instance the
+	// provisioning runs on. BaseImageName is the newest image of that name.
+	BaseImageID         string      `mapstructure:"base_image_ocid"`
+	BaseImageName       string      `mapstructure:"base_image_name"`
+	BaseImageFilter     ImageFilter `mapstructure:"base_image_filter"`
+	Shape               string      `mapstructure:"shape"`
+	ImageName           string      `mapstructure:"image_name"`
+	BootVolumeSizeInGBs int64       `mapstructure:"bootvolumesize"`
+	// ShapeConfig sizes the builder instance on a flexible shape.
+	ShapeConfig ShapeConfig `mapstructure:"shape_config"`
+	// ImageCompatibleShapes are added to the shapes the image is compatible
+	// with once it is created. ImageCapabilities set its capability schema,
+	// e.g. "Compute.Firmware" = ["UEFI_64"], the first value of each being
+	// the default. A single true or false value sets a boolean capability.
+	// Compute.Firmware, Storage.BootVolumeType and Network.AttachmentType
+	// default to the launch_options of the surrogate instance.
+	ImageCompatibleShapes []string            `mapstructure:"image_compatible_shapes"`
+	ImageCapabilities     map[string][]string `mapstructure:"image_capabilities"`
+	// Export exports the image to Object Storage.
+	Export ImageExport `mapstructure:"export"`
+	// CopyToRegions are the regions the image is imported in from its
+	// export, and ImageCopyTargets the compartments, which requires an
+	// export in the OCI, QCOW2 or VMDK format.
+	CopyToRegions    []RegionCopy      `mapstructure:"copy_to_regions"`
+	ImageCopyTargets []ImageCopyTarget `mapstructure:"image_copy_targets"`
+	// ImageMoveToCompartment moves the image to another compartment once it
+	// is created.
+	ImageMoveToCompartment string `mapstructure:"image_move_to_compartment"`
+	// ImageFamily tags the image as the newest of its family, deprecating
+	// the previous one.
+	ImageFamily ImageFamily `mapstructure:"image_family"`
+	// Retention prunes the images of older builds.
+	Retention ImageRetention `mapstructure:"retention"`
 	// Instance
 	InstanceName string `mapstructure:"instance_name"`
 
@@ -81,6 +111,94 @@ type Config struct {
 	// Tagging
 	Tags        map[string]string                 `mapstructure:"tags"`
 	DefinedTags map[string]map[string]interface{} `mapstructure:"defined_tags"`
+	// RunTags are set on the instances and volumes the build creates, with
+	// the packer_build_name and packer_build_uuid tags of the build. Volume
+	// attachments cannot be tagged.
+	RunTags map[string]string `mapstructure:"run_tags"`
+	// runTags are RunTags with the tags of the build.
+	runTags map[string]string
+
+	// Surrogate
+	// CommandWrapper is applied to every command the builder runs on the
+	// instance through the communicator. The `{{.Command}}` variable is
+	// replaced with the command to be run. Defaults to `sudo {{.Command}}`.
+	CommandWrapper string `mapstructure:"command_wrapper"`
+	// AttachmentType is how the surrogate volume is attached to the builder
+	// instance: paravirtualized (the default), iscsi or emulated.
+	AttachmentType string `mapstructure:"attachment_type"`
+	// SurrogateDevice is the consistent device path, e.g.
+	// /dev/oracleoci/oraclevdb, the surrogate volume is attached at. It
+	// requires consistent volume naming on the builder instance.
+	SurrogateDevice string `mapstructure:"surrogate_device"`
+	// SurrogateSource is where the surrogate volume comes from: clone
+	// clones the boot volume of the builder instance, image uses the boot
+	// volume of the target image BaseSurrogateImage (an OCID) or
+	// BaseSurrogateImageName and empty creates a blank block volume of
+	// SurrogateSizeInGBs.
+	// Defaults to image when a target image is set and to clone otherwise.
+	// OCI only boots from boot volumes created from other boot volumes, so
+	// for image a short-lived seed instance is launched on SeedShape from
+	// the target image and terminated preserving its boot volume while it
+	// is still provisioning, before the image ever boots.
+	SurrogateSource        string `mapstructure:"surrogate_source"`
+	BaseSurrogateImage     string `mapstructure:"base_surrogate_image"`
+	BaseSurrogateImageName string `mapstructure:"base_surrogate_image_name"`
+	// surrogateImageFilter resolves BaseSurrogateImageName to the newest
+	// available image of that name, like base_image_name.
+	surrogateImageFilter ImageFilter
+	// SeedShape is the shape of the seed instance. Defaults to
+	// surrogate_shape.
+	SeedShape string `mapstructure:"seed_shape"`
+	// SurrogateShape, SurrogateSubnetID and SurrogateInstanceName are the
+	// shape, subnet and display name of the surrogate instance, and default
+	// to those of the builder instance. SurrogateMetadata replaces metadata
+	// and user_data on the surrogate instance when set.
+	SurrogateShape        string            `mapstructure:"surrogate_shape"`
+	SurrogateSubnetID     string            `mapstructure:"surrogate_subnet_ocid"`
+	SurrogateMetadata     map[string]string `mapstructure:"surrogate_metadata"`
+	SurrogateInstanceName string            `mapstructure:"surrogate_instance_name"`
+	// SurrogateShapeConfig sizes the surrogate and seed instances on a
+	// flexible shape. Defaults to shape_config when surrogate_shape is the
+	// builder's shape.
+	SurrogateShapeConfig ShapeConfig `mapstructure:"surrogate_shape_config"`
+	// LaunchOptions are the launch options and platform configuration of
+	// the surrogate instance and the launch mode of the image.
+	LaunchOptions LaunchOptions `mapstructure:"launch_options"`
+	// SurrogateSizeInGBs is the size of the surrogate volume. Defaults to
+	// bootvolumesize for clone, to bootvolumesize or 50 for empty and to
+	// the size of the image for image.
+	SurrogateSizeInGBs int64 `mapstructure:"surrogate_size_in_gbs"`
+	// MountPath is the directory on the instance where the surrogate volume
+	// is mounted. Defaults to /mnt.
+	MountPath       string          `mapstructure:"mount_path"`
+	SurrogateLayout SurrogateLayout `mapstructure:"surrogate_layout"`
+	ZFSRoot         ZFSRoot         `mapstructure:"zfs_root"`
+	// UseChroot runs the provisioners chrooted into mount_path, with
+	// ChrootMounts mounted inside it. Each ChrootMounts entry is a list of
+	// the filesystem type (or "bind"), the source and the mount point within
+	// the chroot. Defaults to bind mounts of /dev, /proc and /sys. Requires
+	// a surrogate_layout partition mounted on / or zfs_root.
+	UseChroot    bool       `mapstructure:"use_chroot"`
+	ChrootMounts [][]string `mapstructure:"chroot_mounts"`
+	// CopyRoot copies the root filesystem of the builder instance into
+	// mount_path before provisioning, followed by CopyRootPaths when they are
+	// separate mounts on the builder instance (defaults to /boot and
+	// /boot/efi). CopyRootExcludes are passed to rsync as --exclude patterns.
+	// Requires a surrogate_layout partition mounted on / or zfs_root.
+	// SELinuxRelabel requests a full relabel on the first boot of the image.
+	CopyRoot         bool     `mapstructure:"copy_root"`
+	CopyRootPaths    []string `mapstructure:"copy_root_paths"`
+	CopyRootExcludes []string `mapstructure:"copy_root_excludes"`
+	SELinuxRelabel   bool     `mapstructure:"selinux_relabel"`
+
+	Bootloader Bootloader `mapstructure:"bootloader"`
+	// VerifySurrogate boots the surrogate instance and checks it is
+	// reachable before creating the image.
+	VerifySurrogate SurrogateVerification `mapstructure:"verify_surrogate"`
+	// SurrogateVolumes are additional block volumes attached to the builder
+	// instance and then to the surrogate instance at launch. The image only
+	// captures the boot volume, so they are not part of it.
+	SurrogateVolumes []SurrogateVolume `mapstructure:"surrogate_volumes"`
 
 	ctx interpolate.Context
 }
@@ -95,6 +213,13 @@ func (c *Config) Prepare(raws ...interface{}) error {
 	err := config.Decode(c, &config.DecodeOpts{
 		Interpolate:        true,
 		InterpolateContext: &c.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{
+				"command_wrapper",
+				"verify_surrogate",
+				"export",
+			},
+		},
 	}, raws...)
 	if err != nil {
 		return fmt.Errorf("Failed to mapstructure Config: %+v", err)
@@ -249,10 +374,25 @@ func (c *Config) Prepare(raws ...interface{}) error {
 			errs, errors.New("'subnet_ocid' must be specified"))
 	}
 
-	if c.BaseImageID == "" && c.BaseImageName == "" {
+	baseImages := 0
+	for _, set := range []bool{c.BaseImageID != "", c.BaseImageName != "", c.BaseImageFilter.isSet()} {
+		if set {
+			baseImages++
+		}
+	}
+	switch baseImages {
+	case 0:
 		errs = packer.MultiErrorAppend(
-			errs, errors.New("Either 'base_image_ocid' or 'base_image_name' must be specified"))
+			errs, errors.New("Either 'base_image_ocid', 'base_image_name' or 'base_image_filter' must be specified"))
+	case 1:
+	default:
+		errs = packer.MultiErrorAppend(
+			errs, errors.New("Only one of 'base_image_ocid', 'base_image_name' or 'base_image_filter' may be specified"))
+	}
+	if c.BaseImageName != "" {
+		c.BaseImageFilter = ImageFilter{DisplayName: c.BaseImageName, MostRecent: true}
 	}
+	errs = packer.MultiErrorAppend(errs, c.BaseImageFilter.Prepare("base_image_filter", c.CompartmentID)...)
 
 	// Validate tag lengths. TODO (hlowndes) maximum number of tags allowed.
 	if c.Tags != nil {
@@ -278,6 +418,9 @@ func (c *Config) Prepare(raws ...interface{}) error {
 		}
 	}
 
+	errs = packer.MultiErrorAppend(errs, validateRunTags(c.RunTags)...)
+	c.runTags = runTags(c.RunTags, c.PackerBuildName, uuid.TimeOrderedUUID())
+
 	if c.ImageName == "" {
 		name, err := interpolate.Render("packer-{{timestamp}}", nil)
 		if err != nil {
@@ -288,6 +431,180 @@ func (c *Config) Prepare(raws ...interface{}) error {
 		}
 	}
 
+	for name, values := range c.ImageCapabilities {
+		if len(values) == 0 {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+				"image_capabilities: %s must have at least one value", name))
+		}
+	}
+
+	if c.CommandWrapper == "" {
+		c.CommandWrapper = "sudo {{.Command}}"
+	}
+
+	if c.MountPath == "" {
+		c.MountPath = "/mnt"
+	}
+
+	if c.AttachmentType == "" {
+		c.AttachmentType = "paravirtualized"
+	}
+	switch c.AttachmentType {
+	case "paravirtualized", "iscsi", "emulated":
+	default:
+		errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+			"attachment_type must be one of paravirtualized, iscsi or emulated, got %q", c.AttachmentType))
+	}
+	if c.SurrogateDevice != "" && !strings.HasPrefix(c.SurrogateDevice, "/dev/oracleoci/") {
+		errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+			"surrogate_device must be a /dev/oracleoci/ path, got %q", c.SurrogateDevice))
+	}
+
+	hasTargetImage := c.BaseSurrogateImage != "" || c.BaseSurrogateImageName != ""
+	if c.SurrogateSource == "" {
+		c.SurrogateSource = "clone"
+		if hasTargetImage {
+			c.SurrogateSource = "image"
+		}
+	}
+	switch c.SurrogateSource {
+	case "clone", "empty":
+		if hasTargetImage {
+			errs = packer.MultiErrorAppend(errs, errors.New(
+				"base_surrogate_image and base_surrogate_image_name require surrogate_source image"))
+		}
+		if c.SurrogateSizeInGBs == 0 {
+			c.SurrogateSizeInGBs = c.BootVolumeSizeInGBs
+		}
+		if c.SurrogateSizeInGBs == 0 && c.SurrogateSource == "empty" {
+			c.SurrogateSizeInGBs = 50
+		}
+	case "image":
+		if !hasTargetImage {
+			errs = packer.MultiErrorAppend(errs, errors.New(
+				"surrogate_source image requires base_surrogate_image or base_surrogate_image_name"))
+		}
+		if c.BaseSurrogateImage != "" && c.BaseSurrogateImageName != "" {
+			errs = packer.MultiErrorAppend(errs, errors.New(
+				"Only one of 'base_surrogate_image' or 'base_surrogate_image_name' may be specified"))
+		}
+		c.surrogateImageFilter = ImageFilter{DisplayName: c.BaseSurrogateImageName, MostRecent: true}
+		errs = packer.MultiErrorAppend(errs, c.surrogateImageFilter.Prepare("base_surrogate_image_name", c.CompartmentID)...)
+	default:
+		errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+			"surrogate_source must be one of clone, image or empty, got %q", c.SurrogateSource))
+	}
+	if c.SurrogateShape == "" {
+		c.SurrogateShape = c.Shape
+	}
+	if !c.SurrogateShapeConfig.isSet() && c.SurrogateShape == c.Shape {
+		c.SurrogateShapeConfig = c.ShapeConfig
+	}
+	errs = packer.MultiErrorAppend(errs, c.ShapeConfig.Prepare("shape_config")...)
+	errs = packer.MultiErrorAppend(errs, c.SurrogateShapeConfig.Prepare("surrogate_shape_config")...)
+	if c.SurrogateSubnetID == "" {
+		c.SurrogateSubnetID = c.SubnetID
+	}
+	if c.SurrogateInstanceName == "" {
+		c.SurrogateInstanceName = c.InstanceName
+	}
+	if c.SeedShape == "" {
+		c.SeedShape = c.SurrogateShape
+	}
+	if c.SurrogateSizeInGBs != 0 && c.SurrogateSizeInGBs < 50 {
+		errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+			"surrogate_size_in_gbs must be at least 50, got %d", c.SurrogateSizeInGBs))
+	}
+
+	errs = packer.MultiErrorAppend(errs, c.SurrogateLayout.Prepare()...)
+	errs = packer.MultiErrorAppend(errs, c.ZFSRoot.Prepare(&c.SurrogateLayout)...)
+	errs = packer.MultiErrorAppend(errs, c.Bootloader.Prepare(&c.ZFSRoot, c.UseChroot)...)
+	errs = packer.MultiErrorAppend(errs, c.LaunchOptions.Prepare(&c.Bootloader)...)
+	for name, values := range c.LaunchOptions.imageCapabilities() {
+		if _, ok := c.ImageCapabilities[name]; ok {
+			continue
+		}
+		if c.ImageCapabilities == nil {
+			c.ImageCapabilities = map[string][]string{}
+		}
+		c.ImageCapabilities[name] = values
+	}
+	errs = packer.MultiErrorAppend(errs, c.Export.Prepare(&c.ctx)...)
+	copyRegions := map[string]bool{}
+	for i := range c.CopyToRegions {
+		errs = packer.MultiErrorAppend(errs, c.CopyToRegions[i].Prepare(i, c.CompartmentID)...)
+		if region := c.CopyToRegions[i].Region; copyRegions[region] {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("copy_to_regions: region %q is listed more than once", region))
+		}
+		copyRegions[c.CopyToRegions[i].Region] = true
+	}
+	for i := range c.ImageCopyTargets {
+		errs = packer.MultiErrorAppend(errs, c.ImageCopyTargets[i].Prepare(i)...)
+	}
+	if len(c.CopyToRegions) > 0 || len(c.ImageCopyTargets) > 0 {
+		switch {
+		case !c.Export.isSet():
+			errs = packer.MultiErrorAppend(errs, errors.New("copy_to_regions and image_copy_targets require export"))
+		case c.Export.Format == "VHD" || c.Export.Format == "VDI":
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf(
+				"copy_to_regions and image_copy_targets require an export format of OCI, QCOW2 or VMDK, got %q", c.Export.Format))
+		}
+	}
+	// the compartment the image ends up in
+	imageCompartment := c.CompartmentID
+	if c.ImageMoveToCompartment != "" {
+		imageCompartment = c.ImageMoveToCompartment
+	}
+	errs = packer.MultiErrorAppend(errs, c.ImageFamily.Prepare(imageCompartment)...)
+	errs = packer.MultiErrorAppend(errs, c.Retention.Prepare(imageCompartment)...)
+	errs = packer.MultiErrorAppend(errs, c.VerifySurrogate.Prepare(c.PackerUserVars, &c.ctx)...)
+	if c.VerifySurrogate.enabled && c.Comm.Type == "none" {
+		errs = packer.MultiErrorAppend(
+			errs, errors.New("verify_surrogate requires a communicator"))
+	}
+	for i := range c.SurrogateVolumes {
+		errs = packer.MultiErrorAppend(errs, c.SurrogateVolumes[i].Prepare(i, c.ImageName)...)
+	}
+	if len(c.SurrogateVolumes) != 0 && c.AttachmentType == "emulated" {
+		errs = packer.MultiErrorAppend(errs, errors.New(
+			"surrogate_volumes can't be attached at launch with attachment_type emulated"))
+	}
+
+	if len(c.ChrootMounts) == 0 {
+		c.ChrootMounts = [][]string{
+			{"bind", "/dev", "/dev"},
+			{"bind", "/proc", "/proc"},
+			{"bind", "/sys", "/sys"},
+		}
+	}
+	if c.CopyRootPaths == nil {
+		c.CopyRootPaths = []string{"/boot", "/boot/efi"}
+	}
+	for _, p := range c.CopyRootPaths {
+		if !filepath.IsAbs(p) {
+			errs = packer.MultiErrorAppend(
+				errs, fmt.Errorf("copy_root_paths must be absolute paths, got %q", p))
+		}
+	}
+
+	surrogateRoot := c.SurrogateLayout.hasRootMount() || c.ZFSRoot.enabled
+	if c.UseChroot && !surrogateRoot {
+		errs = packer.MultiErrorAppend(errs, errors.New(
+			"use_chroot requires a surrogate_layout partition mounted on / or zfs_root"))
+	}
+	if c.CopyRoot && !surrogateRoot {
+		errs = packer.MultiErrorAppend(errs, errors.New(
+			"copy_root requires a surrogate_layout partition mounted on / or zfs_root"))
+	}
+
+	for _, mounts := range c.ChrootMounts {
+		if len(mounts) != 3 {
+			errs = packer.MultiErrorAppend(
+				errs, errors.New("Each chroot_mounts entry should be three elements."))
+			break
+		}
+	}
+
 	// Optional UserData config
 	if c.UserData != "" && c.UserDataFile != "" {
 		errs = packer.MultiErrorAppend(errs, fmt.Errorf("Only one of user_data or user_data_file can be specified."))
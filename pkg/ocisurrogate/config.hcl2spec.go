@@ -70,15 +70,52 @@ type FlatConfig struct {
 	CompartmentID             *string                      `mapstructure:"compartment_ocid" cty:"compartment_ocid"`
 	BaseImageID               *string                      `mapstructure:"base_image_ocid" cty:"base_image_ocid"`
 	BaseImageName             *string                      `mapstructure:"base_image_name" cty:"base_image_name"`
+	BaseImageFilter           *FlatImageFilter             `mapstructure:"base_image_filter" cty:"base_image_filter"`
 	Shape                     *string                      `mapstructure:"shape" cty:"shape"`
 	ImageName                 *string                      `mapstructure:"image_name" cty:"image_name"`
 	BootVolumeSizeInGBs       *int64                       `mapstructure:"bootvolumesize" cty:"bootvolumesize"`
+	ShapeConfig               *FlatShapeConfig             `mapstructure:"shape_config" cty:"shape_config"`
+	ImageCompatibleShapes     []string                     `mapstructure:"image_compatible_shapes" cty:"image_compatible_shapes"`
+	ImageCapabilities         map[string][]string          `mapstructure:"image_capabilities" cty:"image_capabilities"`
+	Export                    *FlatImageExport             `mapstructure:"export" cty:"export"`
+	CopyToRegions             []FlatRegionCopy             `mapstructure:"copy_to_regions" cty:"copy_to_regions"`
+	ImageCopyTargets          []FlatImageCopyTarget        `mapstructure:"image_copy_targets" cty:"image_copy_targets"`
+	ImageMoveToCompartment    *string                      `mapstructure:"image_move_to_compartment" cty:"image_move_to_compartment"`
+	ImageFamily               *FlatImageFamily             `mapstructure:"image_family" cty:"image_family"`
+	Retention                 *FlatImageRetention          `mapstructure:"retention" cty:"retention"`
 	InstanceName              *string                      `mapstructure:"instance_name" cty:"instance_name"`
 	Metadata                  map[string]string            `mapstructure:"metadata" cty:"metadata"`
 	UserData                  *string                      `mapstructure:"user_data" cty:"user_data"`
 	UserDataFile              *string                      `mapstructure:"user_data_file" cty:"user_data_file"`
 	SubnetID                  *string                      `mapstructure:"subnet_ocid" cty:"subnet_ocid"`
 	Tags                      map[string]string            `mapstructure:"tags" cty:"tags"`
+	RunTags                   map[string]string            `mapstructure:"run_tags" cty:"run_tags"`
+	CommandWrapper            *string                      `mapstructure:"command_wrapper" cty:"command_wrapper"`
+	AttachmentType            *string                      `mapstructure:"attachment_type" cty:"attachment_type"`
+	SurrogateDevice           *string                      `mapstructure:"surrogate_device" cty:"surrogate_device"`
+	SurrogateSource           *string                      `mapstructure:"surrogate_source" cty:"surrogate_source"`
+	BaseSurrogateImage        *string                      `mapstructure:"base_surrogate_image" cty:"base_surrogate_image"`
+	BaseSurrogateImageName    *string                      `mapstructure:"base_surrogate_image_name" cty:"base_surrogate_image_name"`
+	SeedShape                 *string                      `mapstructure:"seed_shape" cty:"seed_shape"`
+	SurrogateShape            *string                      `mapstructure:"surrogate_shape" cty:"surrogate_shape"`
+	SurrogateSubnetID         *string                      `mapstructure:"surrogate_subnet_ocid" cty:"surrogate_subnet_ocid"`
+	SurrogateMetadata         map[string]string            `mapstructure:"surrogate_metadata" cty:"surrogate_metadata"`
+	SurrogateInstanceName     *string                      `mapstructure:"surrogate_instance_name" cty:"surrogate_instance_name"`
+	SurrogateShapeConfig      *FlatShapeConfig             `mapstructure:"surrogate_shape_config" cty:"surrogate_shape_config"`
+	LaunchOptions             *FlatLaunchOptions           `mapstructure:"launch_options" cty:"launch_options"`
+	SurrogateSizeInGBs        *int64                       `mapstructure:"surrogate_size_in_gbs" cty:"surrogate_size_in_gbs"`
+	MountPath                 *string                      `mapstructure:"mount_path" cty:"mount_path"`
+	SurrogateLayout           *FlatSurrogateLayout         `mapstructure:"surrogate_layout" cty:"surrogate_layout"`
+	ZFSRoot                   *FlatZFSRoot                 `mapstructure:"zfs_root" cty:"zfs_root"`
+	UseChroot                 *bool                        `mapstructure:"use_chroot" cty:"use_chroot"`
+	ChrootMounts              [][]string                   `mapstructure:"chroot_mounts" cty:"chroot_mounts"`
+	CopyRoot                  *bool                        `mapstructure:"copy_root" cty:"copy_root"`
+	CopyRootPaths             []string                     `mapstructure:"copy_root_paths" cty:"copy_root_paths"`
+	CopyRootExcludes          []string                     `mapstructure:"copy_root_excludes" cty:"copy_root_excludes"`
+	SELinuxRelabel            *bool                        `mapstructure:"selinux_relabel" cty:"selinux_relabel"`
+	Bootloader                *FlatBootloader              `mapstructure:"bootloader" cty:"bootloader"`
+	VerifySurrogate           *FlatSurrogateVerification   `mapstructure:"verify_surrogate" cty:"verify_surrogate"`
+	SurrogateVolumes          []FlatSurrogateVolume        `mapstructure:"surrogate_volumes" cty:"surrogate_volumes"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -155,15 +192,52 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"compartment_ocid":             &hcldec.AttrSpec{Name: "compartment_ocid", Type: cty.String, Required: false},
 		"base_image_ocid":              &hcldec.AttrSpec{Name: "base_image_ocid", Type: cty.String, Required: false},
 		"base_image_name":              &hcldec.AttrSpec{Name: "base_image_name", Type: cty.String, Required: false},
+		"base_image_filter":            &hcldec.BlockSpec{TypeName: "base_image_filter", Nested: hcldec.ObjectSpec((*FlatImageFilter)(nil).HCL2Spec())},
 		"shape":                        &hcldec.AttrSpec{Name: "shape", Type: cty.String, Required: false},
 		"image_name":                   &hcldec.AttrSpec{Name: "image_name", Type: cty.String, Required: false},
 		"bootvolumesize":               &hcldec.AttrSpec{Name: "bootvolumesize", Type: cty.Number, Required: false},
+		"shape_config":                 &hcldec.BlockSpec{TypeName: "shape_config", Nested: hcldec.ObjectSpec((*FlatShapeConfig)(nil).HCL2Spec())},
+		"image_compatible_shapes":      &hcldec.AttrSpec{Name: "image_compatible_shapes", Type: cty.List(cty.String), Required: false},
+		"image_capabilities":           &hcldec.AttrSpec{Name: "image_capabilities", Type: cty.Map(cty.List(cty.String)), Required: false},
+		"export":                       &hcldec.BlockSpec{TypeName: "export", Nested: hcldec.ObjectSpec((*FlatImageExport)(nil).HCL2Spec())},
+		"copy_to_regions":              &hcldec.BlockListSpec{TypeName: "copy_to_regions", Nested: hcldec.ObjectSpec((*FlatRegionCopy)(nil).HCL2Spec())},
+		"image_copy_targets":           &hcldec.BlockListSpec{TypeName: "image_copy_targets", Nested: hcldec.ObjectSpec((*FlatImageCopyTarget)(nil).HCL2Spec())},
+		"image_move_to_compartment":    &hcldec.AttrSpec{Name: "image_move_to_compartment", Type: cty.String, Required: false},
+		"image_family":                 &hcldec.BlockSpec{TypeName: "image_family", Nested: hcldec.ObjectSpec((*FlatImageFamily)(nil).HCL2Spec())},
+		"retention":                    &hcldec.BlockSpec{TypeName: "retention", Nested: hcldec.ObjectSpec((*FlatImageRetention)(nil).HCL2Spec())},
 		"instance_name":                &hcldec.AttrSpec{Name: "instance_name", Type: cty.String, Required: false},
 		"metadata":                     &hcldec.BlockAttrsSpec{TypeName: "metadata", ElementType: cty.String, Required: false},
 		"user_data":                    &hcldec.AttrSpec{Name: "user_data", Type: cty.String, Required: false},
 		"user_data_file":               &hcldec.AttrSpec{Name: "user_data_file", Type: cty.String, Required: false},
 		"subnet_ocid":                  &hcldec.AttrSpec{Name: "subnet_ocid", Type: cty.String, Required: false},
 		"tags":                         &hcldec.BlockAttrsSpec{TypeName: "tags", ElementType: cty.String, Required: false},
+		"run_tags":                     &hcldec.BlockAttrsSpec{TypeName: "run_tags", ElementType: cty.String, Required: false},
+		"command_wrapper":              &hcldec.AttrSpec{Name: "command_wrapper", Type: cty.String, Required: false},
+		"attachment_type":              &hcldec.AttrSpec{Name: "attachment_type", Type: cty.String, Required: false},
+		"surrogate_device":             &hcldec.AttrSpec{Name: "surrogate_device", Type: cty.String, Required: false},
+		"surrogate_source":             &hcldec.AttrSpec{Name: "surrogate_source", Type: cty.String, Required: false},
+		"base_surrogate_image":         &hcldec.AttrSpec{Name: "base_surrogate_image", Type: cty.String, Required: false},
+		"base_surrogate_image_name":    &hcldec.AttrSpec{Name: "base_surrogate_image_name", Type: cty.String, Required: false},
+		"seed_shape":                   &hcldec.AttrSpec{Name: "seed_shape", Type: cty.String, Required: false},
+		"surrogate_shape":              &hcldec.AttrSpec{Name: "surrogate_shape", Type: cty.String, Required: false},
+		"surrogate_subnet_ocid":        &hcldec.AttrSpec{Name: "surrogate_subnet_ocid", Type: cty.String, Required: false},
+		"surrogate_metadata":           &hcldec.BlockAttrsSpec{TypeName: "surrogate_metadata", ElementType: cty.String, Required: false},
+		"surrogate_instance_name":      &hcldec.AttrSpec{Name: "surrogate_instance_name", Type: cty.String, Required: false},
+		"surrogate_shape_config":       &hcldec.BlockSpec{TypeName: "surrogate_shape_config", Nested: hcldec.ObjectSpec((*FlatShapeConfig)(nil).HCL2Spec())},
+		"launch_options":               &hcldec.BlockSpec{TypeName: "launch_options", Nested: hcldec.ObjectSpec((*FlatLaunchOptions)(nil).HCL2Spec())},
+		"surrogate_size_in_gbs":        &hcldec.AttrSpec{Name: "surrogate_size_in_gbs", Type: cty.Number, Required: false},
+		"mount_path":                   &hcldec.AttrSpec{Name: "mount_path", Type: cty.String, Required: false},
+		"surrogate_layout":             &hcldec.BlockSpec{TypeName: "surrogate_layout", Nested: hcldec.ObjectSpec((*FlatSurrogateLayout)(nil).HCL2Spec())},
+		"zfs_root":                     &hcldec.BlockSpec{TypeName: "zfs_root", Nested: hcldec.ObjectSpec((*FlatZFSRoot)(nil).HCL2Spec())},
+		"use_chroot":                   &hcldec.AttrSpec{Name: "use_chroot", Type: cty.Bool, Required: false},
+		"chroot_mounts":                &hcldec.AttrSpec{Name: "chroot_mounts", Type: cty.List(cty.List(cty.String)), Required: false},
+		"copy_root":                    &hcldec.AttrSpec{Name: "copy_root", Type: cty.Bool, Required: false},
+		"copy_root_paths":              &hcldec.AttrSpec{Name: "copy_root_paths", Type: cty.List(cty.String), Required: false},
+		"copy_root_excludes":           &hcldec.AttrSpec{Name: "copy_root_excludes", Type: cty.List(cty.String), Required: false},
+		"selinux_relabel":              &hcldec.AttrSpec{Name: "selinux_relabel", Type: cty.Bool, Required: false},
+		"bootloader":                   &hcldec.BlockSpec{TypeName: "bootloader", Nested: hcldec.ObjectSpec((*FlatBootloader)(nil).HCL2Spec())},
+		"verify_surrogate":             &hcldec.BlockSpec{TypeName: "verify_surrogate", Nested: hcldec.ObjectSpec((*FlatSurrogateVerification)(nil).HCL2Spec())},
+		"surrogate_volumes":            &hcldec.BlockListSpec{TypeName: "surrogate_volumes", Nested: hcldec.ObjectSpec((*FlatSurrogateVolume)(nil).HCL2Spec())},
 	}
 	return s
 }
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/oracle/oci-go-sdk/common"
+	"github.com/oracle/oci-go-sdk/v65/common"
 )
 
 // rawConfigurationProvider allows a user to simply construct a configuration
@@ -74,3 +74,8 @@ func (p rawConfigurationProvider) Region() (string, error) {
 	}
 	return p.region, nil
 }
+
+// AuthType reports that the provider signs requests as a user principal.
+func (p rawConfigurationProvider) AuthType() (common.AuthConfig, error) {
+	return common.AuthConfig{AuthType: common.UserPrincipal}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-ini/ini"
 )
@@ -114,7 +115,7 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration %+v", errs)
 		}
 
-		tenancy, err := c.ConfigProvider.TenancyOCID()
+		tenancy, err := c.ConfigProvider().TenancyOCID()
 		if err != nil {
 			t.Fatalf("Unexpected error getting tenancy ocid: %v", err)
 		}
@@ -133,7 +134,7 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration %+v", errs)
 		}
 
-		region, err := c.ConfigProvider.Region()
+		region, err := c.ConfigProvider().Region()
 		if err != nil {
 			t.Fatalf("Unexpected error getting region: %v", err)
 		}
@@ -185,7 +186,7 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration %+v", errs)
 		}
 
-		user, _ := c.ConfigProvider.UserOCID()
+		user, _ := c.ConfigProvider().UserOCID()
 		if user != expected {
 			t.Errorf("Expected ConfigProvider.UserOCID: %s, got %s", expected, user)
 		}
@@ -201,7 +202,7 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration %+v", errs)
 		}
 
-		tenancy, _ := c.ConfigProvider.TenancyOCID()
+		tenancy, _ := c.ConfigProvider().TenancyOCID()
 		if tenancy != expected {
 			t.Errorf("Expected ConfigProvider.TenancyOCID: %s, got %s", expected, tenancy)
 		}
@@ -217,7 +218,7 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration %+v", errs)
 		}
 
-		region, _ := c.ConfigProvider.Region()
+		region, _ := c.ConfigProvider().Region()
 		if region != expected {
 			t.Errorf("Expected ConfigProvider.Region: %s, got %s", expected, region)
 		}
@@ -233,11 +234,358 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("Unexpected error in configuration: %+v", errs)
 		}
 
-		fingerprint, _ := c.ConfigProvider.KeyFingerprint()
+		fingerprint, _ := c.ConfigProvider().KeyFingerprint()
 		if fingerprint != expected {
 			t.Errorf("Expected ConfigProvider.KeyFingerprint: %s, got %s", expected, fingerprint)
 		}
 	})
+
+	t.Run("AttachmentTypeDefaulted", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["surrogate_device"] = "/dev/oracleoci/oraclevdb"
+		raw["surrogate_layout"] = map[string]interface{}{
+			"partition": []map[string]interface{}{{"type": "8300", "filesystem": "ext4"}},
+		}
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if c.AttachmentType != "paravirtualized" {
+			t.Errorf("Expected attachment_type paravirtualized, got %s", c.AttachmentType)
+		}
+		if c.SurrogateLayout.Device != "" {
+			t.Errorf("Expected surrogate_layout device to be resolved at attach time, got %s", c.SurrogateLayout.Device)
+		}
+	})
+
+	t.Run("InvalidAttachmentType", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["attachment_type"] = "nvme"
+		raw["surrogate_device"] = "/dev/sdb"
+
+		_, errs := NewConfig(raw)
+		if errs == nil {
+			t.Fatalf("Expected error in configuration")
+		}
+
+		for _, expected := range []string{"attachment_type", "surrogate_device"} {
+			if !strings.Contains(errs.Error(), expected) {
+				t.Errorf("Expected %q to contain '%s'", errs.Error(), expected)
+			}
+		}
+	})
+
+	t.Run("SurrogateVolumesAttachmentType", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["attachment_type"] = "emulated"
+		raw["surrogate_volumes"] = []map[string]interface{}{{"size_in_gbs": 50}}
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "surrogate_volumes can't be attached at launch") {
+			t.Fatalf("Expected a launch attachment error, got %v", errs)
+		}
+	})
+
+	t.Run("CopyToRegions", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["copy_to_regions"] = []map[string]interface{}{
+			{"region": "us-phoenix-1"},
+			{"region": "us-phoenix-1"},
+		}
+
+		_, errs := NewConfig(raw)
+		if errs == nil {
+			t.Fatalf("Expected error in configuration")
+		}
+		for _, expected := range []string{"require export", "more than once"} {
+			if !strings.Contains(errs.Error(), expected) {
+				t.Errorf("Expected %q to contain '%s'", errs.Error(), expected)
+			}
+		}
+
+		raw["copy_to_regions"] = []map[string]interface{}{{"region": "us-phoenix-1"}}
+		raw["export"] = map[string]interface{}{"bucket": "images", "namespace": "ns"}
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+		if c.CopyToRegions[0].CompartmentID != c.CompartmentID {
+			t.Errorf("Expected the copy compartment to default to compartment_ocid, got %s", c.CopyToRegions[0].CompartmentID)
+		}
+	})
+
+	t.Run("ImageCopyTargets", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["image_copy_targets"] = []map[string]interface{}{
+			{"region": "us-phoenix-1"},
+		}
+
+		_, errs := NewConfig(raw)
+		if errs == nil {
+			t.Fatalf("Expected error in configuration")
+		}
+		for _, expected := range []string{"require export", "compartment_ocid must be specified"} {
+			if !strings.Contains(errs.Error(), expected) {
+				t.Errorf("Expected %q to contain '%s'", errs.Error(), expected)
+			}
+		}
+
+		raw["image_copy_targets"] = []map[string]interface{}{{"compartment_ocid": "ocid1.compartment.partner"}}
+		raw["export"] = map[string]interface{}{"bucket": "images", "namespace": "ns", "publish_duration": "168h"}
+		raw["image_move_to_compartment"] = "ocid1.compartment.golden"
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+		if c.Export.PublishDuration != 168*time.Hour {
+			t.Errorf("Expected publish_duration of 168h, got %s", c.Export.PublishDuration)
+		}
+	})
+
+	t.Run("RunTags", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["run_tags"] = map[string]string{"team": "platform"}
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+		if c.runTags["team"] != "platform" || c.runTags[runTagBuildUUID] == "" {
+			t.Errorf("Expected the run tags with a build UUID, got %v", c.runTags)
+		}
+
+		other, _ := NewConfig(raw)
+		if other.runTags[runTagBuildUUID] == c.runTags[runTagBuildUUID] {
+			t.Errorf("Expected a unique build UUID per build, got %s twice", c.runTags[runTagBuildUUID])
+		}
+
+		raw["run_tags"] = map[string]string{runTagBuildName: "mine"}
+		if _, errs := NewConfig(raw); errs == nil || !strings.Contains(errs.Error(), "set by the builder") {
+			t.Fatalf("Expected a run_tags error, got %v", errs)
+		}
+	})
+
+	t.Run("ImageFamily", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["image_family"] = map[string]interface{}{"name": "ol8-base"}
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "deprecated_tag_namespace") {
+			t.Fatalf("Expected an image_family error, got %v", errs)
+		}
+
+		raw["image_family"] = map[string]interface{}{"name": "ol8-base", "deprecated_tag_namespace": "Images"}
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+		if c.ImageFamily.CompartmentID != c.CompartmentID {
+			t.Errorf("Expected the family compartment to default to compartment_ocid, got %s", c.ImageFamily.CompartmentID)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["retention"] = map[string]interface{}{"keep_last": 3}
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "name_prefix or tags") {
+			t.Fatalf("Expected a retention error, got %v", errs)
+		}
+
+		raw["retention"] = map[string]interface{}{"name_prefix": "nightly-", "keep_last": 3, "max_age": "720h"}
+		raw["image_move_to_compartment"] = "ocid1.compartment.golden"
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+		if c.Retention.CompartmentID != "ocid1.compartment.golden" {
+			t.Errorf("Expected the retention compartment to default to image_move_to_compartment, got %s", c.Retention.CompartmentID)
+		}
+		if c.Retention.MaxAge != 720*time.Hour {
+			t.Errorf("Expected max_age of 720h, got %s", c.Retention.MaxAge)
+		}
+	})
+
+	t.Run("CopyRootRequiresSurrogateRoot", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["copy_root"] = true
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "copy_root") {
+			t.Fatalf("Expected a copy_root error, got %v", errs)
+		}
+
+		raw["zfs_root"] = map[string]interface{}{"vdev": "/dev/sdb"}
+		if _, errs := NewConfig(raw); errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+	})
+
+	t.Run("UseChrootRequiresSurrogateRoot", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["use_chroot"] = true
+		raw["surrogate_layout"] = map[string]interface{}{
+			"partition": []map[string]interface{}{{"type": "8300", "filesystem": "ext4", "mount_point": "/boot"}},
+		}
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "use_chroot") {
+			t.Fatalf("Expected a use_chroot error, got %v", errs)
+		}
+
+		raw["surrogate_layout"] = map[string]interface{}{
+			"partition": []map[string]interface{}{{"type": "8300", "filesystem": "ext4", "mount_point": "/"}},
+		}
+		if _, errs := NewConfig(raw); errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+	})
+
+	t.Run("BaseImageName", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		delete(raw, "base_image_ocid")
+		raw["base_image_name"] = "Oracle-Linux-7.8-2020.06.30-0"
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if c.BaseImageFilter.DisplayName != "Oracle-Linux-7.8-2020.06.30-0" || !c.BaseImageFilter.MostRecent {
+			t.Errorf("Expected base_image_name to select the newest image of that name, got %+v", c.BaseImageFilter)
+		}
+	})
+
+	t.Run("SeveralBaseImages", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["base_image_filter"] = map[string]interface{}{"operating_system": "Oracle Linux"}
+
+		_, errs := NewConfig(raw)
+		if errs == nil || !strings.Contains(errs.Error(), "Only one of") {
+			t.Fatalf("Expected an error about several base images, got %v", errs)
+		}
+	})
+
+	t.Run("SurrogateSourceDefaulted", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["bootvolumesize"] = 100
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if c.SurrogateSource != "clone" {
+			t.Errorf("Expected surrogate_source clone, got %s", c.SurrogateSource)
+		}
+		if c.SurrogateSizeInGBs != 100 {
+			t.Errorf("Expected surrogate_size_in_gbs to default to bootvolumesize, got %d", c.SurrogateSizeInGBs)
+		}
+	})
+
+	t.Run("TargetImage", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["bootvolumesize"] = 100
+		raw["base_surrogate_image_name"] = "Oracle-Linux-8.2-2020.08.27-0"
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if c.SurrogateSource != "image" {
+			t.Errorf("Expected surrogate_source image, got %s", c.SurrogateSource)
+		}
+		if c.SurrogateSizeInGBs != 0 {
+			t.Errorf("Expected surrogate_size_in_gbs to default to the image size, got %d", c.SurrogateSizeInGBs)
+		}
+		if c.SeedShape != c.Shape {
+			t.Errorf("Expected seed_shape to default to shape, got %s", c.SeedShape)
+		}
+		if c.surrogateImageFilter.DisplayName != "Oracle-Linux-8.2-2020.08.27-0" || !c.surrogateImageFilter.MostRecent {
+			t.Errorf("Expected base_surrogate_image_name to select the newest image of that name, got %+v", c.surrogateImageFilter)
+		}
+	})
+
+	t.Run("SurrogateInstanceDefaulted", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["instance_name"] = "builder"
+		raw["shape_config"] = map[string]interface{}{"ocpus": 2}
+		raw["surrogate_shape"] = "BM.Standard.E3.128"
+		raw["surrogate_source"] = "empty"
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if c.SeedShape != "BM.Standard.E3.128" {
+			t.Errorf("Expected seed_shape to default to surrogate_shape, got %s", c.SeedShape)
+		}
+		if c.SurrogateSubnetID != c.SubnetID {
+			t.Errorf("Expected surrogate_subnet_ocid to default to subnet_ocid, got %s", c.SurrogateSubnetID)
+		}
+		if c.SurrogateShapeConfig.isSet() {
+			t.Errorf("Expected surrogate_shape_config not to default to shape_config on another shape")
+		}
+		if c.SurrogateInstanceName != "builder" {
+			t.Errorf("Expected surrogate_instance_name to default to instance_name, got %s", c.SurrogateInstanceName)
+		}
+	})
+
+	t.Run("ImageCapabilitiesFromLaunchOptions", func(t *testing.T) {
+		raw := testConfig(cfgFile)
+		raw["launch_options"] = map[string]interface{}{
+			"firmware":         "UEFI_64",
+			"boot_volume_type": "PARAVIRTUALIZED",
+		}
+		raw["image_capabilities"] = map[string][]string{
+			"Storage.BootVolumeType": {"PARAVIRTUALIZED", "ISCSI"},
+		}
+
+		c, errs := NewConfig(raw)
+		if errs != nil {
+			t.Fatalf("Unexpected error in configuration: %+v", errs)
+		}
+
+		if firmware := c.ImageCapabilities["Compute.Firmware"]; len(firmware) != 1 || firmware[0] != "UEFI_64" {
+			t.Errorf("Expected Compute.Firmware to default to the launch firmware, got %v", firmware)
+		}
+		if volumeTypes := c.ImageCapabilities["Storage.BootVolumeType"]; len(volumeTypes) != 2 {
+			t.Errorf("Expected image_capabilities to take precedence, got %v", volumeTypes)
+		}
+		if _, ok := c.ImageCapabilities["Network.AttachmentType"]; ok {
+			t.Errorf("Expected no Network.AttachmentType without network_type")
+		}
+	})
+
+	t.Run("InvalidSurrogateSource", func(t *testing.T) {
+		for _, tc := range []struct {
+			source, image, expected string
+		}{
+			{"image", "", "requires base_surrogate_image"},
+			{"clone", "ocid1.image.target", "require surrogate_source image"},
+			{"blank", "", "surrogate_source must be"},
+		} {
+			raw := testConfig(cfgFile)
+			raw["surrogate_source"] = tc.source
+			raw["base_surrogate_image"] = tc.image
+			raw["surrogate_size_in_gbs"] = 20
+
+			_, errs := NewConfig(raw)
+			if errs == nil {
+				t.Fatalf("Expected error in configuration")
+			}
+			for _, expected := range []string{tc.expected, "surrogate_size_in_gbs"} {
+				if !strings.Contains(errs.Error(), expected) {
+					t.Errorf("Expected %q to contain '%s'", errs.Error(), expected)
+				}
+			}
+		}
+	})
 }
 
 // BaseTestConfig creates the base (DEFAULT) config including a temporary key
@@ -0,0 +1,63 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// saveConsoleHistory writes the serial console history of an instance to a
+// local file when the build failed or runs with -debug, so that boot failures
+// can be diagnosed. The path of the file is appended to the build error. It
+// is meant to be called from the Cleanup of the step that launched the
+// instance, before terminating it.
+func saveConsoleHistory(state multistep.StateBag, instanceID string, name string) {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	rawErr, failed := state.GetOk("error")
+	_, halted := state.GetOk(multistep.StateHalted)
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	if !failed && !halted && !cancelled && !config.PackerDebug {
+		return
+	}
+
+	path := fmt.Sprintf("oci_%s_%s_console.log", config.PackerBuildName, name)
+	ui.Say(fmt.Sprintf("Saving console history of the %s instance to %s...", name, path))
+
+	if err := writeConsoleHistory(driver, instanceID, path); err != nil {
+		ui.Error(fmt.Sprintf("Error saving console history of the %s instance: %s", name, err))
+		return
+	}
+
+	if failed {
+		state.Put("error", fmt.Errorf("%s (console history of the %s instance saved to %s)", rawErr, name, path))
+	}
+}
+
+// writeConsoleHistory captures the console history of an instance and writes
+// its content to path.
+func writeConsoleHistory(driver Driver, instanceID string, path string) error {
+	ctx := context.TODO()
+
+	id, err := driver.CaptureConsoleHistory(ctx, instanceID)
+	if id != "" {
+		defer driver.DeleteConsoleHistory(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := driver.GetConsoleHistoryContent(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
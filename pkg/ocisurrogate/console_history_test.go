@@ -0,0 +1,61 @@
+package ocisurrogate
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSaveConsoleHistory(t *testing.T) {
+	state := testState()
+	state.Put("error", errors.New("Timeout waiting for SSH."))
+	driver := state.Get("driver").(*driverMock)
+	driver.ConsoleHistoryContent = "Booting the kernel.\n"
+
+	saveConsoleHistory(state, "ocid1...", "surrogate")
+
+	path := "oci__surrogate_console.log"
+	defer os.Remove(path)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("should have written the console history: %s", err)
+	}
+	if string(content) != driver.ConsoleHistoryContent {
+		t.Errorf("bad console history: %q", content)
+	}
+	if driver.DeleteConsoleHistoryID != driver.CaptureConsoleHistoryID {
+		t.Errorf("should have deleted the captured console history")
+	}
+
+	err = state.Get("error").(error)
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("error should mention %s, got: %s", path, err)
+	}
+}
+
+func TestSaveConsoleHistory_Succeeded(t *testing.T) {
+	state := testState()
+
+	saveConsoleHistory(state, "ocid1...", "surrogate")
+
+	driver := state.Get("driver").(*driverMock)
+	if driver.CaptureConsoleHistoryID != "" {
+		t.Fatalf("should NOT capture the console history of a successful build")
+	}
+}
+
+func TestSaveConsoleHistory_CaptureErr(t *testing.T) {
+	state := testState()
+	state.Put("error", errors.New("error"))
+	driver := state.Get("driver").(*driverMock)
+	driver.CaptureConsoleHistoryErr = errors.New("capture error")
+
+	saveConsoleHistory(state, "ocid1...", "builder")
+
+	if err := state.Get("error").(error); err.Error() != "error" {
+		t.Errorf("build error should be left untouched, got: %s", err)
+	}
+}
@@ -2,23 +2,55 @@ package ocisurrogate
 
 import (
 	"context"
+	"time"
 
-	"github.com/oracle/oci-go-sdk/core"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 // Driver interfaces between the builder steps and the OCI SDK.
 type Driver interface {
-	CreateInstance(ctx context.Context, publicKey string, surrogateVolumeId string) (string, error)
+	CreateInstance(ctx context.Context, publicKey string, imageId string, surrogateVolumeId string, volumes []*createdVolume) (string, error)
+	GetImage(ctx context.Context, id string) (core.Image, error)
+	ListImages(ctx context.Context, filter *ImageFilter) ([]core.Image, error)
+	CreateSeedInstance(ctx context.Context, imageId string, sizeInGBs int64) (string, error)
+	WaitForBootVolumeID(ctx context.Context, instanceId string) (string, error)
+	TagBootVolume(ctx context.Context, id string) error
 	CreateBootClone(ctx context.Context, InstanceId string) (string, error)
 	AttachBootClone(ctx context.Context, InstanceId string, VolumeId string) (string, error)
 	DetachBootClone(ctx context.Context, VolumeId string) (string, error)
+	GetVolumeAttachment(ctx context.Context, id string) (core.VolumeAttachment, error)
 	CreateImage(ctx context.Context, id string) (core.Image, error)
+	AddImageShapeCompatibility(ctx context.Context, imageId string, shape string) error
+	CreateImageCapabilitySchema(ctx context.Context, imageId string, capabilities map[string][]string) (string, error)
+	ExportImage(ctx context.Context, imageId string, export *ImageExport, objectName string) (string, error)
 	DeleteImage(ctx context.Context, id string) error
+	ChangeImageCompartment(ctx context.Context, imageId string, compartmentId string) error
+	UpdateImageDefinedTags(ctx context.Context, imageId string, definedTags map[string]map[string]interface{}) error
+	CreatePreauthenticatedRequest(ctx context.Context, export *ImageExport, objectName string, name string, expires time.Time) (string, string, error)
+	DeletePreauthenticatedRequest(ctx context.Context, export *ImageExport, id string) error
+	ImportImage(ctx context.Context, region string, compartmentId string, sourceURI string, format string) (core.Image, error)
+	DeleteRegionalImage(ctx context.Context, region string, id string) error
 	GetInstanceIP(ctx context.Context, id string) (string, error)
 	TerminateInstance(ctx context.Context, id string) error
+	TerminateInstancePreservingBootVolume(ctx context.Context, id string) error
+	CaptureConsoleHistory(ctx context.Context, instanceId string) (string, error)
+	GetConsoleHistoryContent(ctx context.Context, id string) (string, error)
+	DeleteConsoleHistory(ctx context.Context, id string) error
 	DeleteBootVolume(ctx context.Context, id string) error
+	CreateVolume(ctx context.Context, volume *SurrogateVolume) (string, error)
+	AttachVolume(ctx context.Context, instanceId string, volumeId string, device string) (string, error)
+	DetachVolume(ctx context.Context, attachmentId string) error
+	DeleteVolume(ctx context.Context, id string) error
+	ListInstances(ctx context.Context, compartmentId string) ([]core.Instance, error)
+	ListBootVolumes(ctx context.Context, compartmentId string) ([]core.BootVolume, error)
+	ListVolumes(ctx context.Context, compartmentId string) ([]core.Volume, error)
+	ListVolumeAttachments(ctx context.Context, compartmentId string) ([]core.VolumeAttachment, error)
+	ListInstanceVolumeAttachments(ctx context.Context, instanceId string) ([]core.VolumeAttachment, error)
 	WaitForImageCreation(ctx context.Context, id string) error
+	WaitForRegionalImageCreation(ctx context.Context, region string, id string) error
+	WaitForWorkRequest(ctx context.Context, id string) error
 	WaitForInstanceState(ctx context.Context, id string, waitStates []string, terminalState string) error
 	WaitForBootVolumeState(ctx context.Context, id string, waitStates []string, terminalState string) error
+	WaitForVolumeState(ctx context.Context, id string, waitStates []string, terminalState string) error
 	WaitForVolumeAttachmentState(ctx context.Context, id string, waitStates []string, terminalState string) error
 }
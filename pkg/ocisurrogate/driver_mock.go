@@ -2,8 +2,10 @@ package ocisurrogate
 
 import (
 	"context"
+	"fmt"
+	"time"
 
-	"github.com/oracle/oci-go-sdk/core"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 // driverMock implements the Driver interface and communicates with Oracle
@@ -12,38 +14,203 @@ type driverMock struct {
 	CreateInstanceID  string
 	CreateInstanceErr error
 
-	CreateBootCloneID string
+	CreateInstanceImageID string
+	// CreateInstanceVolumes are the volumes attached at launch.
+	CreateInstanceVolumes []*createdVolume
+
+	Images        []core.Image
+	ListImagesErr error
+	GetImageErr   error
+
+	CreateSeedInstanceID      string
+	CreateSeedInstanceImageID string
+	CreateSeedInstanceErr     error
+
+	WaitForBootVolumeIDErr error
+
+	TagBootVolumeID  string
+	TagBootVolumeErr error
+
+	CreateBootCloneID  string
 	CreateBootCloneErr error
 
+	AttachBootCloneID  string
+	AttachBootCloneErr error
+
+	DetachBootCloneID  string
+	DetachBootCloneErr error
+
+	GetVolumeAttachmentResult core.VolumeAttachment
+	GetVolumeAttachmentErr    error
+
+	DeleteBootVolumeID  string
+	DeleteBootVolumeErr error
+	// DeletedBootVolumeIDs are all the boot volumes deleted.
+	DeletedBootVolumeIDs []string
+
+	CreateVolumeIDs []string
+	CreateVolumeErr error
+
+	AttachVolumeIDs []string
+	AttachVolumeErr error
+
+	DetachVolumeIDs []string
+	DetachVolumeErr error
+
+	DeleteVolumeIDs []string
+	DeleteVolumeErr error
+
+	Instances        []core.Instance
+	ListInstancesErr error
+
+	BootVolumes       []core.BootVolume
+	Volumes           []core.Volume
+	VolumeAttachments []core.VolumeAttachment
+
 	CreateImageID  string
 	CreateImageErr error
 
+	ImageCompatibleShapes         []string
+	AddImageShapeCompatibilityErr error
+
+	ImageCapabilities              map[string][]string
+	CreateImageCapabilitySchemaErr error
+
+	ExportImageObjectNames []string
+	ExportImageErr         error
+
 	DeleteImageID  string
 	DeleteImageErr error
+	// DeletedImageIDs are all the images deleted.
+	DeletedImageIDs []string
+
+	ChangeImageCompartmentID  string
+	ChangeImageCompartmentErr error
+
+	UpdateImageDefinedTagsID  string
+	UpdateImageDefinedTags    map[string]map[string]interface{}
+	UpdateImageDefinedTagsErr error
+
+	PreauthenticatedRequestNames     []string
+	CreatePreauthenticatedRequestErr error
+
+	DeletePreauthenticatedRequestID  string
+	DeletePreauthenticatedRequestErr error
+
+	// ImportImageCompartmentIDs maps the regions images are imported in to
+	// their compartment.
+	ImportImageCompartmentIDs map[string]string
+	ImportImageSourceURI      string
+	ImportImageErr            error
+
+	DeleteRegionalImageIDs []string
+	DeleteRegionalImageErr error
 
 	GetInstanceIPErr error
 
 	TerminateInstanceID  string
 	TerminateInstanceErr error
+	// TerminatedInstanceIDs are all the instances terminated.
+	TerminatedInstanceIDs []string
+
+	PreservedBootVolumeInstanceID string
+
+	CaptureConsoleHistoryID  string
+	CaptureConsoleHistoryErr error
+
+	ConsoleHistoryContent       string
+	GetConsoleHistoryContentErr error
+
+	DeleteConsoleHistoryID  string
+	DeleteConsoleHistoryErr error
 
 	WaitForImageCreationErr error
 
+	WaitForRegionalImageCreationErr error
+
+	WaitForWorkRequestErr error
+
 	WaitForInstanceStateErr error
+	// WaitForInstanceStateErrState limits WaitForInstanceStateErr to waits
+	// for this terminal state when set.
+	WaitForInstanceStateErrState string
+	// WaitForInstanceStates are the terminal states waited for.
+	WaitForInstanceStates []string
+
+	WaitForBootVolumeStateErr error
+
+	WaitForVolumeStateErr error
+
+	WaitForVolumeAttachmentStateErr error
 
 	cfg *Config
 }
 
 // CreateInstance creates a new compute instance.
-func (d *driverMock) CreateInstance(ctx context.Context, publicKey string) (string, error) {
+func (d *driverMock) CreateInstance(ctx context.Context, publicKey string, imageId string, surrogateVolumeId string, volumes []*createdVolume) (string, error) {
 	if d.CreateInstanceErr != nil {
 		return "", d.CreateInstanceErr
 	}
 
+	d.CreateInstanceImageID = imageId
+	d.CreateInstanceVolumes = volumes
+
 	d.CreateInstanceID = "ocid1..."
 
 	return d.CreateInstanceID, nil
 }
 
+// GetImage returns an image.
+func (d *driverMock) GetImage(ctx context.Context, id string) (core.Image, error) {
+	if d.GetImageErr != nil {
+		return core.Image{}, d.GetImageErr
+	}
+
+	name := "base-image"
+	return core.Image{Id: &id, DisplayName: &name}, nil
+}
+
+// ListImages returns Images.
+func (d *driverMock) ListImages(ctx context.Context, filter *ImageFilter) ([]core.Image, error) {
+	if d.ListImagesErr != nil {
+		return nil, d.ListImagesErr
+	}
+
+	return d.Images, nil
+}
+
+// CreateSeedInstance creates a seed instance for its boot volume.
+func (d *driverMock) CreateSeedInstance(ctx context.Context, imageId string, sizeInGBs int64) (string, error) {
+	if d.CreateSeedInstanceErr != nil {
+		return "", d.CreateSeedInstanceErr
+	}
+
+	d.CreateSeedInstanceImageID = imageId
+	d.CreateSeedInstanceID = "ocid1.instance.seed"
+
+	return d.CreateSeedInstanceID, nil
+}
+
+// WaitForBootVolumeID returns the boot volume of a provisioning instance.
+func (d *driverMock) WaitForBootVolumeID(ctx context.Context, instanceId string) (string, error) {
+	if d.WaitForBootVolumeIDErr != nil {
+		return "", d.WaitForBootVolumeIDErr
+	}
+
+	return "ocid1.bootvolume.seed", nil
+}
+
+// TagBootVolume mocks setting the run tags on a boot volume.
+func (d *driverMock) TagBootVolume(ctx context.Context, id string) error {
+	if d.TagBootVolumeErr != nil {
+		return d.TagBootVolumeErr
+	}
+
+	d.TagBootVolumeID = id
+
+	return nil
+}
+
 // CreateBootClone creates a clone of the boot disk abd attaches it to the instance.
 func (d *driverMock) CreateBootClone(ctx context.Context, InstanceId string) (string, error) {
 	if d.CreateBootCloneErr != nil {
@@ -55,6 +222,41 @@ func (d *driverMock) CreateBootClone(ctx context.Context, InstanceId string) (st
 	return d.CreateBootCloneID, nil
 }
 
+// AttachBootClone attaches a clone of the boot disk to the instance.
+func (d *driverMock) AttachBootClone(ctx context.Context, InstanceId string, VolumeId string) (string, error) {
+	if d.AttachBootCloneErr != nil {
+		return "", d.AttachBootCloneErr
+	}
+
+	d.AttachBootCloneID = "ocid1..."
+
+	return d.AttachBootCloneID, nil
+}
+
+// DetachBootClone detaches a clone of the boot disk from the instance.
+func (d *driverMock) DetachBootClone(ctx context.Context, VolumeAttachmentId string) (string, error) {
+	if d.DetachBootCloneErr != nil {
+		return "", d.DetachBootCloneErr
+	}
+
+	d.DetachBootCloneID = VolumeAttachmentId
+
+	return d.DetachBootCloneID, nil
+}
+
+// GetVolumeAttachment returns the details of a volume attachment, a
+// paravirtualized attachment unless GetVolumeAttachmentResult is set.
+func (d *driverMock) GetVolumeAttachment(ctx context.Context, id string) (core.VolumeAttachment, error) {
+	if d.GetVolumeAttachmentErr != nil {
+		return nil, d.GetVolumeAttachmentErr
+	}
+	if d.GetVolumeAttachmentResult != nil {
+		return d.GetVolumeAttachmentResult, nil
+	}
+
+	return core.ParavirtualizedVolumeAttachment{Id: &id}, nil
+}
+
 // CreateImage creates a new custom image.
 func (d *driverMock) CreateImage(ctx context.Context, id string) (core.Image, error) {
 	if d.CreateImageErr != nil {
@@ -64,6 +266,39 @@ func (d *driverMock) CreateImage(ctx context.Context, id string) (core.Image, er
 	return core.Image{Id: &id}, nil
 }
 
+// AddImageShapeCompatibility makes an image compatible with a shape.
+func (d *driverMock) AddImageShapeCompatibility(ctx context.Context, imageId string, shape string) error {
+	if d.AddImageShapeCompatibilityErr != nil {
+		return d.AddImageShapeCompatibilityErr
+	}
+
+	d.ImageCompatibleShapes = append(d.ImageCompatibleShapes, shape)
+
+	return nil
+}
+
+// CreateImageCapabilitySchema sets the capabilities of an image.
+func (d *driverMock) CreateImageCapabilitySchema(ctx context.Context, imageId string, capabilities map[string][]string) (string, error) {
+	if d.CreateImageCapabilitySchemaErr != nil {
+		return "", d.CreateImageCapabilitySchemaErr
+	}
+
+	d.ImageCapabilities = capabilities
+
+	return "ocid1.computeimagecapabilityschema", nil
+}
+
+// ExportImage exports an image to Object Storage.
+func (d *driverMock) ExportImage(ctx context.Context, imageId string, export *ImageExport, objectName string) (string, error) {
+	if d.ExportImageErr != nil {
+		return "", d.ExportImageErr
+	}
+
+	d.ExportImageObjectNames = append(d.ExportImageObjectNames, objectName)
+
+	return "ocid1.workrequest", nil
+}
+
 // DeleteImage mocks deleting a custom image.
 func (d *driverMock) DeleteImage(ctx context.Context, id string) error {
 	if d.DeleteImageErr != nil {
@@ -71,6 +306,81 @@ func (d *driverMock) DeleteImage(ctx context.Context, id string) error {
 	}
 
 	d.DeleteImageID = id
+	d.DeletedImageIDs = append(d.DeletedImageIDs, id)
+
+	return nil
+}
+
+// ChangeImageCompartment mocks moving a custom image to another
+// compartment.
+func (d *driverMock) ChangeImageCompartment(ctx context.Context, imageId string, compartmentId string) error {
+	if d.ChangeImageCompartmentErr != nil {
+		return d.ChangeImageCompartmentErr
+	}
+
+	d.ChangeImageCompartmentID = compartmentId
+
+	return nil
+}
+
+// UpdateImageDefinedTags mocks replacing the defined tags of a custom image.
+func (d *driverMock) UpdateImageDefinedTags(ctx context.Context, imageId string, definedTags map[string]map[string]interface{}) error {
+	if d.UpdateImageDefinedTagsErr != nil {
+		return d.UpdateImageDefinedTagsErr
+	}
+
+	d.UpdateImageDefinedTagsID = imageId
+	d.UpdateImageDefinedTags = definedTags
+
+	return nil
+}
+
+// CreatePreauthenticatedRequest mocks creating a pre-authenticated request
+// reading an object.
+func (d *driverMock) CreatePreauthenticatedRequest(ctx context.Context, export *ImageExport, objectName string, name string, expires time.Time) (string, string, error) {
+	if d.CreatePreauthenticatedRequestErr != nil {
+		return "", "", d.CreatePreauthenticatedRequestErr
+	}
+
+	d.PreauthenticatedRequestNames = append(d.PreauthenticatedRequestNames, name)
+
+	return fmt.Sprintf("/p/secret/n/%s/b/%s/o/%s", export.Namespace, export.Bucket, objectName), "ocid1.par." + name, nil
+}
+
+// DeletePreauthenticatedRequest mocks deleting a pre-authenticated request.
+func (d *driverMock) DeletePreauthenticatedRequest(ctx context.Context, export *ImageExport, id string) error {
+	if d.DeletePreauthenticatedRequestErr != nil {
+		return d.DeletePreauthenticatedRequestErr
+	}
+
+	d.DeletePreauthenticatedRequestID = id
+
+	return nil
+}
+
+// ImportImage mocks importing an image in a region.
+func (d *driverMock) ImportImage(ctx context.Context, region string, compartmentId string, sourceURI string, format string) (core.Image, error) {
+	if d.ImportImageErr != nil {
+		return core.Image{}, d.ImportImageErr
+	}
+
+	if d.ImportImageCompartmentIDs == nil {
+		d.ImportImageCompartmentIDs = map[string]string{}
+	}
+	d.ImportImageCompartmentIDs[region] = compartmentId
+	d.ImportImageSourceURI = sourceURI
+
+	id := "ocid1.image.oc1." + region
+	return core.Image{Id: &id}, nil
+}
+
+// DeleteRegionalImage mocks deleting a custom image of a region.
+func (d *driverMock) DeleteRegionalImage(ctx context.Context, region string, id string) error {
+	if d.DeleteRegionalImageErr != nil {
+		return d.DeleteRegionalImageErr
+	}
+
+	d.DeleteRegionalImageIDs = append(d.DeleteRegionalImageIDs, id)
 
 	return nil
 }
@@ -93,18 +403,189 @@ func (d *driverMock) TerminateInstance(ctx context.Context, id string) error {
 	}
 
 	d.TerminateInstanceID = id
+	d.TerminatedInstanceIDs = append(d.TerminatedInstanceIDs, id)
+
+	return nil
+}
+
+// TerminateInstancePreservingBootVolume terminates a compute instance and
+// keeps its boot volume.
+func (d *driverMock) TerminateInstancePreservingBootVolume(ctx context.Context, id string) error {
+	if d.TerminateInstanceErr != nil {
+		return d.TerminateInstanceErr
+	}
+
+	d.PreservedBootVolumeInstanceID = id
+
+	return nil
+}
+
+// CaptureConsoleHistory captures the serial console history of an instance.
+func (d *driverMock) CaptureConsoleHistory(ctx context.Context, instanceId string) (string, error) {
+	if d.CaptureConsoleHistoryErr != nil {
+		return "", d.CaptureConsoleHistoryErr
+	}
+
+	d.CaptureConsoleHistoryID = "ocid1..."
+
+	return d.CaptureConsoleHistoryID, nil
+}
+
+// GetConsoleHistoryContent returns the content of a captured console history.
+func (d *driverMock) GetConsoleHistoryContent(ctx context.Context, id string) (string, error) {
+	if d.GetConsoleHistoryContentErr != nil {
+		return "", d.GetConsoleHistoryContentErr
+	}
+
+	return d.ConsoleHistoryContent, nil
+}
+
+// DeleteConsoleHistory deletes a captured console history.
+func (d *driverMock) DeleteConsoleHistory(ctx context.Context, id string) error {
+	if d.DeleteConsoleHistoryErr != nil {
+		return d.DeleteConsoleHistoryErr
+	}
+
+	d.DeleteConsoleHistoryID = id
+
+	return nil
+}
+
+// DeleteBootVolume deletes a boot volume.
+func (d *driverMock) DeleteBootVolume(ctx context.Context, id string) error {
+	if d.DeleteBootVolumeErr != nil {
+		return d.DeleteBootVolumeErr
+	}
+
+	d.DeleteBootVolumeID = id
+	d.DeletedBootVolumeIDs = append(d.DeletedBootVolumeIDs, id)
 
 	return nil
 }
 
+// CreateVolume creates a block volume.
+func (d *driverMock) CreateVolume(ctx context.Context, volume *SurrogateVolume) (string, error) {
+	if d.CreateVolumeErr != nil {
+		return "", d.CreateVolumeErr
+	}
+
+	id := fmt.Sprintf("ocid1.volume.%d", len(d.CreateVolumeIDs))
+	d.CreateVolumeIDs = append(d.CreateVolumeIDs, id)
+
+	return id, nil
+}
+
+// AttachVolume attaches a block volume to an instance.
+func (d *driverMock) AttachVolume(ctx context.Context, instanceId string, volumeId string, device string) (string, error) {
+	if d.AttachVolumeErr != nil {
+		return "", d.AttachVolumeErr
+	}
+
+	id := fmt.Sprintf("ocid1.volumeattachment.%d", len(d.AttachVolumeIDs))
+	d.AttachVolumeIDs = append(d.AttachVolumeIDs, id)
+
+	return id, nil
+}
+
+// DetachVolume detaches a block volume from an instance.
+func (d *driverMock) DetachVolume(ctx context.Context, attachmentId string) error {
+	if d.DetachVolumeErr != nil {
+		return d.DetachVolumeErr
+	}
+
+	d.DetachVolumeIDs = append(d.DetachVolumeIDs, attachmentId)
+
+	return nil
+}
+
+// DeleteVolume deletes a block volume.
+func (d *driverMock) DeleteVolume(ctx context.Context, id string) error {
+	if d.DeleteVolumeErr != nil {
+		return d.DeleteVolumeErr
+	}
+
+	d.DeleteVolumeIDs = append(d.DeleteVolumeIDs, id)
+
+	return nil
+}
+
+// ListInstances returns Instances.
+func (d *driverMock) ListInstances(ctx context.Context, compartmentId string) ([]core.Instance, error) {
+	if d.ListInstancesErr != nil {
+		return nil, d.ListInstancesErr
+	}
+
+	return d.Instances, nil
+}
+
+// ListBootVolumes returns BootVolumes.
+func (d *driverMock) ListBootVolumes(ctx context.Context, compartmentId string) ([]core.BootVolume, error) {
+	return d.BootVolumes, nil
+}
+
+// ListVolumes returns Volumes.
+func (d *driverMock) ListVolumes(ctx context.Context, compartmentId string) ([]core.Volume, error) {
+	return d.Volumes, nil
+}
+
+// ListVolumeAttachments returns VolumeAttachments.
+func (d *driverMock) ListVolumeAttachments(ctx context.Context, compartmentId string) ([]core.VolumeAttachment, error) {
+	return d.VolumeAttachments, nil
+}
+
+// ListInstanceVolumeAttachments returns the VolumeAttachments of an
+// instance.
+func (d *driverMock) ListInstanceVolumeAttachments(ctx context.Context, instanceId string) ([]core.VolumeAttachment, error) {
+	var attachments []core.VolumeAttachment
+	for _, attachment := range d.VolumeAttachments {
+		if *attachment.GetInstanceId() == instanceId {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
 // WaitForImageCreation waits for a provisioning custom image to reach the
 // "AVAILABLE" state.
 func (d *driverMock) WaitForImageCreation(ctx context.Context, id string) error {
 	return d.WaitForImageCreationErr
 }
 
+// WaitForRegionalImageCreation waits for a custom image of a region to
+// reach the "AVAILABLE" state.
+func (d *driverMock) WaitForRegionalImageCreation(ctx context.Context, region string, id string) error {
+	return d.WaitForRegionalImageCreationErr
+}
+
+// WaitForWorkRequest waits for a work request to succeed.
+func (d *driverMock) WaitForWorkRequest(ctx context.Context, id string) error {
+	return d.WaitForWorkRequestErr
+}
+
 // WaitForInstanceState waits for an instance to reach the a given terminal
 // state.
 func (d *driverMock) WaitForInstanceState(ctx context.Context, id string, waitStates []string, terminalState string) error {
+	d.WaitForInstanceStates = append(d.WaitForInstanceStates, terminalState)
+	if d.WaitForInstanceStateErrState != "" && d.WaitForInstanceStateErrState != terminalState {
+		return nil
+	}
 	return d.WaitForInstanceStateErr
 }
+
+// WaitForBootVolumeState waits for a boot volume to reach the a given terminal
+// state.
+func (d *driverMock) WaitForBootVolumeState(ctx context.Context, id string, waitStates []string, terminalState string) error {
+	return d.WaitForBootVolumeStateErr
+}
+
+// WaitForVolumeState waits for a block volume to reach the a given terminal
+// state.
+func (d *driverMock) WaitForVolumeState(ctx context.Context, id string, waitStates []string, terminalState string) error {
+	return d.WaitForVolumeStateErr
+}
+
+// WaitForVolumeAttachmentState waits for a volume attachment to reach the a
+// given terminal state.
+func (d *driverMock) WaitForVolumeAttachmentState(ctx context.Context, id string, waitStates []string, terminalState string) error {
+	return d.WaitForVolumeAttachmentStateErr
+}
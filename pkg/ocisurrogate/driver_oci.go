@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	"log"
 
-	core "github.com/oracle/oci-go-sdk/core"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	core "github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+	"github.com/oracle/oci-go-sdk/v65/workrequests"
 )
 
 // driverOCI implements the Driver interface and communicates with Oracle
@@ -16,6 +21,8 @@ type driverOCI struct {
 	computeClient core.ComputeClient
 	blockstorageClient core.BlockstorageClient
 	vcnClient     core.VirtualNetworkClient
+	workRequestClient workrequests.WorkRequestClient
+	objectStorageClient objectstorage.ObjectStorageClient
 	cfg           *Config
 	context       context.Context
 }
@@ -37,72 +44,196 @@ func NewDriverOCI(cfg *Config) (Driver, error) {
 		return nil, err
 	}
 
+	workRequestClient, err := workrequests.NewWorkRequestClientWithConfigurationProvider(cfg.configProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	objectStorageClient, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(cfg.configProvider)
+	if err != nil {
+		return nil, err
+	}
+
 	return &driverOCI{
 		computeClient: coreClient,
 		vcnClient:     vcnClient,
 		cfg:           cfg,
 		blockstorageClient: blockstorageClient,
+		workRequestClient: workRequestClient,
+		objectStorageClient: objectStorageClient,
 	}, nil
 }
 
 // CreateInstance creates a new compute instance.
-func (d *driverOCI) CreateInstance(ctx context.Context, publicKey string, surrogateVolumeId string) (string, error) {
+func (d *driverOCI) CreateInstance(ctx context.Context, publicKey string, imageId string, surrogateVolumeId string, volumes []*createdVolume) (string, error) {
 	metadata := map[string]string{
 		"ssh_authorized_keys": publicKey,
 	}
-	if d.cfg.Metadata != nil {
-		for key, value := range d.cfg.Metadata {
+	if surrogateVolumeId != "" && d.cfg.SurrogateMetadata != nil {
+		for key, value := range d.cfg.SurrogateMetadata {
 			metadata[key] = value
 		}
-	}
-	if d.cfg.UserData != "" {
-		metadata["user_data"] = d.cfg.UserData
-	}
-	var imageId string = d.cfg.BaseImageID
-	if d.cfg.BaseImageName != "" {
-		imageIdList, err := d.computeClient.ListImages(context.TODO(),core.ListImagesRequest{
-			CompartmentId:      &d.cfg.CompartmentID,
-			DisplayName: 		&d.cfg.BaseImageName,
-		})
-		if err != nil {
-			return "", err
+	} else {
+		if d.cfg.Metadata != nil {
+			for key, value := range d.cfg.Metadata {
+				metadata[key] = value
+			}
+		}
+		if d.cfg.UserData != "" {
+			metadata["user_data"] = d.cfg.UserData
 		}
-		imageId = *imageIdList.Items[0].Id
 	}
     var sourcedetails core.InstanceSourceDetails = core.InstanceSourceViaImageDetails{
 		ImageId:            &imageId,
     	BootVolumeSizeInGBs:	&d.cfg.BootVolumeSizeInGBs,
     }
+    shape, subnetId, displayName := d.cfg.Shape, d.cfg.SubnetID, d.cfg.InstanceName
     if surrogateVolumeId != "" {
     	sourcedetails = core.InstanceSourceViaBootVolumeDetails{
     		BootVolumeId: &surrogateVolumeId,
     	}
+    	shape, subnetId, displayName = d.cfg.SurrogateShape, d.cfg.SurrogateSubnetID, d.cfg.SurrogateInstanceName
     }
     instanceDetails := core.LaunchInstanceDetails{
 		AvailabilityDomain: &d.cfg.AvailabilityDomain,
 		CompartmentId:      &d.cfg.CompartmentID,
-		Shape:              &d.cfg.Shape,
+		Shape:              &shape,
 		Metadata:           metadata,
+		FreeformTags:       d.cfg.runTags,
 		CreateVnicDetails:	&core.CreateVnicDetails{
-    		SubnetId:           &d.cfg.SubnetID,
+    		SubnetId:           &subnetId,
     	},
 		SourceDetails:		&sourcedetails,
 	}
 
 	// When empty, the default display name is used.
-	if d.cfg.InstanceName != "" {
-		instanceDetails.DisplayName = &d.cfg.InstanceName
+	if displayName != "" {
+		instanceDetails.DisplayName = &displayName
+	}
+
+	// The volumes are attached at launch so that they are there on the
+	// first boot.
+	for _, volume := range volumes {
+		instanceDetails.LaunchVolumeAttachments = append(instanceDetails.LaunchVolumeAttachments, d.launchAttachVolumeDetails(volume))
 	}
 
-	instance, err := d.computeClient.LaunchInstance(context.TODO(), core.LaunchInstanceRequest{LaunchInstanceDetails: instanceDetails})
+	if surrogateVolumeId != "" {
+		return d.launchInstance(ctx, instanceDetails, &d.cfg.SurrogateShapeConfig, &d.cfg.LaunchOptions)
+	}
 
+	return d.launchInstance(ctx, instanceDetails, &d.cfg.ShapeConfig, &LaunchOptions{})
+}
+
+// launchInstance launches an instance sized by shapeConfig with
+// launchOptions.
+func (d *driverOCI) launchInstance(ctx context.Context, details core.LaunchInstanceDetails, shapeConfig *ShapeConfig, launchOptions *LaunchOptions) (string, error) {
+	details.ShapeConfig = shapeConfig.shapeConfig()
+	details.LaunchOptions = launchOptions.launchOptions()
+	details.PlatformConfig = launchOptions.platformConfig()
+
+	instance, err := d.computeClient.LaunchInstance(ctx, core.LaunchInstanceRequest{LaunchInstanceDetails: details})
 	if err != nil {
 		return "", err
 	}
-
 	return *instance.Id, nil
 }
 
+// CreateSeedInstance launches an instance on seed_shape from the image
+// imageId with a boot volume of sizeInGBs. The instance is only launched for
+// its boot volume and is not reachable over SSH.
+func (d *driverOCI) CreateSeedInstance(ctx context.Context, imageId string, sizeInGBs int64) (string, error) {
+	source := core.InstanceSourceViaImageDetails{
+		ImageId: &imageId,
+	}
+	if sizeInGBs != 0 {
+		source.BootVolumeSizeInGBs = &sizeInGBs
+	}
+	displayName := fmt.Sprintf("%s-seed", d.cfg.ImageName)
+	return d.launchInstance(ctx, core.LaunchInstanceDetails{
+		AvailabilityDomain: &d.cfg.AvailabilityDomain,
+		CompartmentId:      &d.cfg.CompartmentID,
+		Shape:              &d.cfg.SeedShape,
+		DisplayName:        &displayName,
+		FreeformTags:       d.cfg.runTags,
+		CreateVnicDetails: &core.CreateVnicDetails{
+			SubnetId: &d.cfg.SubnetID,
+		},
+		SourceDetails: source,
+	}, &d.cfg.SurrogateShapeConfig, &LaunchOptions{})
+}
+
+// WaitForBootVolumeID waits for the boot volume of an instance that is
+// still provisioning to be attached and returns it. It fails if the instance
+// leaves PROVISIONING first.
+func (d *driverOCI) WaitForBootVolumeID(ctx context.Context, instanceId string) (string, error) {
+	for {
+		res, err := d.computeClient.ListBootVolumeAttachments(ctx, core.ListBootVolumeAttachmentsRequest{
+			AvailabilityDomain: &d.cfg.AvailabilityDomain,
+			CompartmentId:      &d.cfg.CompartmentID,
+			InstanceId:         &instanceId,
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(res.Items) != 0 {
+			return *res.Items[0].BootVolumeId, nil
+		}
+
+		instance, err := d.computeClient.GetInstance(ctx, core.GetInstanceRequest{InstanceId: &instanceId})
+		if err != nil {
+			return "", err
+		}
+		if instance.LifecycleState != core.InstanceLifecycleStateProvisioning {
+			return "", fmt.Errorf("instance %s is %s without a boot volume", instanceId, instance.LifecycleState)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// TagBootVolume sets the run tags on a boot volume the build did not create
+// itself, such as the boot volume of an instance.
+func (d *driverOCI) TagBootVolume(ctx context.Context, id string) error {
+	_, err := d.blockstorageClient.UpdateBootVolume(ctx, core.UpdateBootVolumeRequest{
+		BootVolumeId: &id,
+		UpdateBootVolumeDetails: core.UpdateBootVolumeDetails{
+			FreeformTags: d.cfg.runTags,
+		},
+	})
+	return err
+}
+
+// GetImage returns an image.
+func (d *driverOCI) GetImage(ctx context.Context, id string) (core.Image, error) {
+	res, err := d.computeClient.GetImage(ctx, core.GetImageRequest{ImageId: &id})
+	if err != nil {
+		return core.Image{}, err
+	}
+	return res.Image, nil
+}
+
+// ListImages returns the available images matching the criteria of filter
+// the service filters on, newest first.
+func (d *driverOCI) ListImages(ctx context.Context, filter *ImageFilter) ([]core.Image, error) {
+	var images []core.Image
+	request := filter.listImagesRequest()
+	for {
+		res, err := d.computeClient.ListImages(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, res.Items...)
+		if res.OpcNextPage == nil {
+			return images, nil
+		}
+		request.Page = res.OpcNextPage
+	}
+}
+
 // CreateBootClone creates a clone of the boot disk.
 func (d *driverOCI) CreateBootClone(ctx context.Context, InstanceId string) (string, error) {
 	// Get Instance Details
@@ -127,7 +258,8 @@ func (d *driverOCI) CreateBootClone(ctx context.Context, InstanceId string) (str
 				SourceDetails : core.BootVolumeSourceFromBootVolumeDetails {
 					Id: 	BootVolumeDetails.Items[0].BootVolumeId,
 				},
-				SizeInGBs : &d.cfg.BootVolumeSizeInGBs,
+				SizeInGBs : &d.cfg.SurrogateSizeInGBs,
+				FreeformTags: d.cfg.runTags,
 		},
 	})
 	if err != nil {
@@ -142,18 +274,75 @@ func (d *driverOCI) AttachBootClone(ctx context.Context, InstanceId string, Volu
 	// Get Instance Details
 	log.Printf("Attaching Cloned Volume %s to instance %s", VolumeId,InstanceId)
 	res2, err2 := d.computeClient.AttachVolume(ctx, core.AttachVolumeRequest{
-		 AttachVolumeDetails: core. AttachParavirtualizedVolumeDetails{
-		 	VolumeId:	&VolumeId,
-		 	InstanceId:	&InstanceId,
-		 },
-	},
-	)
+		AttachVolumeDetails: d.attachVolumeDetails(InstanceId, VolumeId, d.cfg.SurrogateDevice),
+	})
 	if err2 != nil {
 		return "", err2
 	}
 	return *res2.VolumeAttachment.GetId(), nil
 }
 
+// attachVolumeDetails returns the details attaching a volume to an instance
+// with attachment_type, at the given consistent device path when not empty.
+func (d *driverOCI) attachVolumeDetails(instanceId string, volumeId string, device string) core.AttachVolumeDetails {
+	var devicePtr *string
+	if device != "" {
+		devicePtr = &device
+	}
+
+	switch d.cfg.AttachmentType {
+	case "iscsi":
+		return core.AttachIScsiVolumeDetails{
+			VolumeId:   &volumeId,
+			InstanceId: &instanceId,
+			Device:     devicePtr,
+		}
+	case "emulated":
+		return core.AttachEmulatedVolumeDetails{
+			VolumeId:   &volumeId,
+			InstanceId: &instanceId,
+			Device:     devicePtr,
+		}
+	}
+	return core.AttachParavirtualizedVolumeDetails{
+		VolumeId:   &volumeId,
+		InstanceId: &instanceId,
+		Device:     devicePtr,
+	}
+}
+
+// launchAttachVolumeDetails returns the details attaching a volume at
+// launch with attachment_type. OCI only attaches paravirtualized and iscsi
+// volumes at launch, which Config.Prepare checks.
+func (d *driverOCI) launchAttachVolumeDetails(volume *createdVolume) core.LaunchAttachVolumeDetails {
+	var device *string
+	if volume.Device != "" {
+		device = &volume.Device
+	}
+
+	if d.cfg.AttachmentType == "iscsi" {
+		return core.LaunchAttachIScsiVolumeDetails{
+			VolumeId: &volume.ID,
+			Device:   device,
+		}
+	}
+	return core.LaunchAttachParavirtualizedVolumeDetails{
+		VolumeId: &volume.ID,
+		Device:   device,
+	}
+}
+
+// GetVolumeAttachment returns the details of a volume attachment.
+func (d *driverOCI) GetVolumeAttachment(ctx context.Context, id string) (core.VolumeAttachment, error) {
+	res, err := d.computeClient.GetVolumeAttachment(ctx, core.GetVolumeAttachmentRequest{
+		VolumeAttachmentId: &id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.VolumeAttachment, nil
+}
+
 // DetachBootClone attaches a clone of the boot disk to the instance.
 func (d *driverOCI) DetachBootClone(ctx context.Context,VolumeAttachmentId string) (string, error) {
 	// Get Instance Details
@@ -177,8 +366,9 @@ func (d *driverOCI) CreateImage(ctx context.Context, id string) (core.Image, err
 		CompartmentId: &d.cfg.CompartmentID,
 		InstanceId:    &id,
 		DisplayName:   &d.cfg.ImageName,
-		FreeformTags:  d.cfg.Tags,
+		FreeformTags:  d.cfg.ImageFamily.imageTags(d.cfg.Tags),
 		DefinedTags:   d.cfg.DefinedTags,
+		LaunchMode:    core.CreateImageDetailsLaunchModeEnum(d.cfg.LaunchOptions.LaunchMode),
 	}})
 
 	if err != nil {
@@ -188,12 +378,212 @@ func (d *driverOCI) CreateImage(ctx context.Context, id string) (core.Image, err
 	return res.Image, nil
 }
 
+// AddImageShapeCompatibility makes an image compatible with a shape.
+func (d *driverOCI) AddImageShapeCompatibility(ctx context.Context, imageId string, shape string) error {
+	_, err := d.computeClient.AddImageShapeCompatibilityEntry(ctx, core.AddImageShapeCompatibilityEntryRequest{
+		ImageId:   &imageId,
+		ShapeName: &shape,
+	})
+	return err
+}
+
+// CreateImageCapabilitySchema sets the capabilities of an image, each a list
+// of allowed values whose first value is the default, against the current
+// version of the global image capability schema.
+func (d *driverOCI) CreateImageCapabilitySchema(ctx context.Context, imageId string, capabilities map[string][]string) (string, error) {
+	globalSchemas, err := d.computeClient.ListComputeGlobalImageCapabilitySchemas(ctx, core.ListComputeGlobalImageCapabilitySchemasRequest{
+		CompartmentId: &d.cfg.CompartmentID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(globalSchemas.Items) == 0 || globalSchemas.Items[0].CurrentVersionName == nil {
+		return "", errors.New("no global image capability schema")
+	}
+
+	schemaData := map[string]core.ImageCapabilitySchemaDescriptor{}
+	for name, values := range capabilities {
+		schemaData[name] = capabilityDescriptor(values)
+	}
+
+	res, err := d.computeClient.CreateComputeImageCapabilitySchema(ctx, core.CreateComputeImageCapabilitySchemaRequest{
+		CreateComputeImageCapabilitySchemaDetails: core.CreateComputeImageCapabilitySchemaDetails{
+			CompartmentId: &d.cfg.CompartmentID,
+			ComputeGlobalImageCapabilitySchemaVersionName: globalSchemas.Items[0].CurrentVersionName,
+			ImageId:    &imageId,
+			SchemaData: schemaData,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return *res.Id, nil
+}
+
+// capabilityDescriptor returns the image capability descriptor allowing
+// values, the first being the default: a boolean for a single true or false,
+// an enuminteger for integers and an enumstring otherwise.
+func capabilityDescriptor(values []string) core.ImageCapabilitySchemaDescriptor {
+	if len(values) == 1 {
+		if b, err := strconv.ParseBool(values[0]); err == nil {
+			return core.BooleanImageCapabilitySchemaDescriptor{
+				Source:       core.ImageCapabilitySchemaDescriptorSourceImage,
+				DefaultValue: &b,
+			}
+		}
+	}
+
+	ints := make([]int, 0, len(values))
+	for _, value := range values {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			break
+		}
+		ints = append(ints, i)
+	}
+	if len(ints) == len(values) {
+		return core.EnumIntegerImageCapabilityDescriptor{
+			Source:       core.ImageCapabilitySchemaDescriptorSourceImage,
+			Values:       ints,
+			DefaultValue: &ints[0],
+		}
+	}
+
+	return core.EnumStringImageCapabilitySchemaDescriptor{
+		Source:       core.ImageCapabilitySchemaDescriptorSourceImage,
+		Values:       values,
+		DefaultValue: &values[0],
+	}
+}
+
+// ExportImage exports an image to the Object Storage bucket of export as
+// objectName and returns the OCID of the work request.
+func (d *driverOCI) ExportImage(ctx context.Context, imageId string, export *ImageExport, objectName string) (string, error) {
+	res, err := d.computeClient.ExportImage(ctx, core.ExportImageRequest{
+		ImageId: &imageId,
+		ExportImageDetails: core.ExportImageViaObjectStorageTupleDetails{
+			BucketName:    &export.Bucket,
+			NamespaceName: &export.Namespace,
+			ObjectName:    &objectName,
+			ExportFormat:  core.ExportImageDetailsExportFormatEnum(export.Format),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return *res.OpcWorkRequestId, nil
+}
+
 // DeleteImage deletes a custom image.
 func (d *driverOCI) DeleteImage(ctx context.Context, id string) error {
 	_, err := d.computeClient.DeleteImage(ctx, core.DeleteImageRequest{ImageId: &id})
 	return err
 }
 
+// ChangeImageCompartment moves a custom image to another compartment.
+func (d *driverOCI) ChangeImageCompartment(ctx context.Context, imageId string, compartmentId string) error {
+	_, err := d.computeClient.ChangeImageCompartment(ctx, core.ChangeImageCompartmentRequest{
+		ImageId: &imageId,
+		ChangeImageCompartmentDetails: core.ChangeImageCompartmentDetails{
+			CompartmentId: &compartmentId,
+		},
+	})
+	return err
+}
+
+// UpdateImageDefinedTags replaces the defined tags of a custom image.
+func (d *driverOCI) UpdateImageDefinedTags(ctx context.Context, imageId string, definedTags map[string]map[string]interface{}) error {
+	_, err := d.computeClient.UpdateImage(ctx, core.UpdateImageRequest{
+		ImageId: &imageId,
+		UpdateImageDetails: core.UpdateImageDetails{
+			DefinedTags: definedTags,
+		},
+	})
+	return err
+}
+
+// CreatePreauthenticatedRequest creates a pre-authenticated request named
+// name reading objectName from the bucket of export until expires, and
+// returns its access URI and OCID.
+func (d *driverOCI) CreatePreauthenticatedRequest(ctx context.Context, export *ImageExport, objectName string, name string, expires time.Time) (string, string, error) {
+	res, err := d.objectStorageClient.CreatePreauthenticatedRequest(ctx, objectstorage.CreatePreauthenticatedRequestRequest{
+		NamespaceName: &export.Namespace,
+		BucketName:    &export.Bucket,
+		CreatePreauthenticatedRequestDetails: objectstorage.CreatePreauthenticatedRequestDetails{
+			Name:        &name,
+			ObjectName:  &objectName,
+			AccessType:  objectstorage.CreatePreauthenticatedRequestDetailsAccessTypeObjectread,
+			TimeExpires: &common.SDKTime{Time: expires},
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return *res.AccessUri, *res.Id, nil
+}
+
+// DeletePreauthenticatedRequest deletes a pre-authenticated request of the
+// bucket of export.
+func (d *driverOCI) DeletePreauthenticatedRequest(ctx context.Context, export *ImageExport, id string) error {
+	_, err := d.objectStorageClient.DeletePreauthenticatedRequest(ctx, objectstorage.DeletePreauthenticatedRequestRequest{
+		NamespaceName: &export.Namespace,
+		BucketName:    &export.Bucket,
+		ParId:         &id,
+	})
+	return err
+}
+
+// ImportImage imports an image named image_name in the compartment of
+// region from sourceURI, an Object Storage URL of an image exported in
+// format.
+func (d *driverOCI) ImportImage(ctx context.Context, region string, compartmentId string, sourceURI string, format string) (core.Image, error) {
+	client, err := d.regionalComputeClient(region)
+	if err != nil {
+		return core.Image{}, err
+	}
+
+	source := core.ImageSourceViaObjectStorageUriDetails{
+		SourceUri: &sourceURI,
+	}
+	// Images exported in the OCI format carry their own metadata.
+	if format != "" && format != "OCI" {
+		source.SourceImageType = core.ImageSourceDetailsSourceImageTypeEnum(format)
+	}
+
+	res, err := client.CreateImage(ctx, core.CreateImageRequest{CreateImageDetails: core.CreateImageDetails{
+		CompartmentId:      &compartmentId,
+		DisplayName:        &d.cfg.ImageName,
+		FreeformTags:       d.cfg.Tags,
+		DefinedTags:        d.cfg.DefinedTags,
+		LaunchMode:         core.CreateImageDetailsLaunchModeEnum(d.cfg.LaunchOptions.LaunchMode),
+		ImageSourceDetails: source,
+	}})
+	if err != nil {
+		return core.Image{}, err
+	}
+	return res.Image, nil
+}
+
+// DeleteRegionalImage deletes a custom image of region.
+func (d *driverOCI) DeleteRegionalImage(ctx context.Context, region string, id string) error {
+	client, err := d.regionalComputeClient(region)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteImage(ctx, core.DeleteImageRequest{ImageId: &id})
+	return err
+}
+
+// regionalComputeClient returns a compute client for region.
+func (d *driverOCI) regionalComputeClient(region string) (core.ComputeClient, error) {
+	client, err := core.NewComputeClientWithConfigurationProvider(d.cfg.configProvider)
+	if err != nil {
+		return client, err
+	}
+	client.SetRegion(region)
+	return client, nil
+}
+
 // GetInstanceIP returns the public or private IP corresponding to the given instance id.
 func (d *driverOCI) GetInstanceIP(ctx context.Context, id string) (string, error) {
 	vnics, err := d.computeClient.ListVnicAttachments(ctx, core.ListVnicAttachmentsRequest{
@@ -243,6 +633,78 @@ func (d *driverOCI) TerminateInstance(ctx context.Context, id string) error {
 	return err
 }
 
+// TerminateInstancePreservingBootVolume terminates a compute instance and
+// keeps its boot volume.
+func (d *driverOCI) TerminateInstancePreservingBootVolume(ctx context.Context, id string) error {
+	preserve := true
+	_, err := d.computeClient.TerminateInstance(ctx, core.TerminateInstanceRequest{
+		InstanceId:         &id,
+		PreserveBootVolume: &preserve,
+	})
+	return err
+}
+
+// CaptureConsoleHistory captures the serial console history of an instance
+// and waits for the capture to succeed.
+func (d *driverOCI) CaptureConsoleHistory(ctx context.Context, instanceId string) (string, error) {
+	res, err := d.computeClient.CaptureConsoleHistory(ctx, core.CaptureConsoleHistoryRequest{
+		CaptureConsoleHistoryDetails: core.CaptureConsoleHistoryDetails{
+			InstanceId: &instanceId,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	id := *res.ConsoleHistory.Id
+
+	err = waitForResourceToReachState(
+		func(string) (string, error) {
+			history, err := d.computeClient.GetConsoleHistory(ctx, core.GetConsoleHistoryRequest{InstanceConsoleHistoryId: &id})
+			if err != nil {
+				return "", err
+			}
+			return string(history.LifecycleState), nil
+		},
+		id,
+		[]string{"REQUESTED", "GETTING-HISTORY"},
+		"SUCCEEDED",
+		0,             //Unlimited Retries
+		2*time.Second, //2 second wait between retries
+	)
+	return id, err
+}
+
+// GetConsoleHistoryContent returns the content of a captured console history.
+func (d *driverOCI) GetConsoleHistoryContent(ctx context.Context, id string) (string, error) {
+	var content strings.Builder
+	for {
+		offset := content.Len()
+		res, err := d.computeClient.GetConsoleHistoryContent(ctx, core.GetConsoleHistoryContentRequest{
+			InstanceConsoleHistoryId: &id,
+			Offset:                   &offset,
+		})
+		if err != nil {
+			return "", err
+		}
+		if res.Value == nil || *res.Value == "" {
+			break
+		}
+		content.WriteString(*res.Value)
+		if res.OpcBytesRemaining == nil || *res.OpcBytesRemaining <= 0 {
+			break
+		}
+	}
+	return content.String(), nil
+}
+
+// DeleteConsoleHistory deletes a captured console history.
+func (d *driverOCI) DeleteConsoleHistory(ctx context.Context, id string) error {
+	_, err := d.computeClient.DeleteConsoleHistory(ctx, core.DeleteConsoleHistoryRequest{
+		InstanceConsoleHistoryId: &id,
+	})
+	return err
+}
+
 // DeleteBootVolume deletes a boot Volume.
 func (d *driverOCI) DeleteBootVolume(ctx context.Context, id string) error {
 	_, err := d.blockstorageClient.DeleteBootVolume(ctx, core.DeleteBootVolumeRequest{
@@ -251,26 +713,197 @@ func (d *driverOCI) DeleteBootVolume(ctx context.Context, id string) error {
 	return err
 }
 
+// CreateVolume creates a block volume, empty or from a source volume or
+// volume backup.
+func (d *driverOCI) CreateVolume(ctx context.Context, volume *SurrogateVolume) (string, error) {
+	details := core.CreateVolumeDetails{
+		AvailabilityDomain: &d.cfg.AvailabilityDomain,
+		CompartmentId:      &d.cfg.CompartmentID,
+		DisplayName:        &volume.Name,
+		VpusPerGB:          volume.VpusPerGB,
+		FreeformTags:       d.cfg.runTags,
+	}
+	if volume.SizeInGBs != 0 {
+		details.SizeInGBs = &volume.SizeInGBs
+	}
+	switch volume.sourceType() {
+	case "volume":
+		details.SourceDetails = core.VolumeSourceFromVolumeDetails{Id: &volume.Source}
+	case "volumeBackup":
+		details.SourceDetails = core.VolumeSourceFromVolumeBackupDetails{Id: &volume.Source}
+	}
+
+	res, err := d.blockstorageClient.CreateVolume(ctx, core.CreateVolumeRequest{
+		CreateVolumeDetails: details,
+	})
+	if err != nil {
+		return "", err
+	}
+	return *res.Volume.Id, nil
+}
+
+// AttachVolume attaches a block volume to an instance with attachment_type,
+// at the given consistent device path when not empty.
+func (d *driverOCI) AttachVolume(ctx context.Context, instanceId string, volumeId string, device string) (string, error) {
+	res, err := d.computeClient.AttachVolume(ctx, core.AttachVolumeRequest{
+		AttachVolumeDetails: d.attachVolumeDetails(instanceId, volumeId, device),
+	})
+	if err != nil {
+		return "", err
+	}
+	return *res.VolumeAttachment.GetId(), nil
+}
+
+// DetachVolume detaches a block volume from an instance.
+func (d *driverOCI) DetachVolume(ctx context.Context, attachmentId string) error {
+	_, err := d.computeClient.DetachVolume(ctx, core.DetachVolumeRequest{
+		VolumeAttachmentId: &attachmentId,
+	})
+	return err
+}
+
+// DeleteVolume deletes a block volume.
+func (d *driverOCI) DeleteVolume(ctx context.Context, id string) error {
+	_, err := d.blockstorageClient.DeleteVolume(ctx, core.DeleteVolumeRequest{
+		VolumeId: &id,
+	})
+	return err
+}
+
+// ListInstances returns the instances of a compartment.
+func (d *driverOCI) ListInstances(ctx context.Context, compartmentId string) ([]core.Instance, error) {
+	var instances []core.Instance
+	request := core.ListInstancesRequest{CompartmentId: &compartmentId}
+	for {
+		res, err := d.computeClient.ListInstances(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, res.Items...)
+		if res.OpcNextPage == nil {
+			return instances, nil
+		}
+		request.Page = res.OpcNextPage
+	}
+}
+
+// ListBootVolumes returns the boot volumes of a compartment.
+func (d *driverOCI) ListBootVolumes(ctx context.Context, compartmentId string) ([]core.BootVolume, error) {
+	var volumes []core.BootVolume
+	request := core.ListBootVolumesRequest{CompartmentId: &compartmentId}
+	for {
+		res, err := d.blockstorageClient.ListBootVolumes(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, res.Items...)
+		if res.OpcNextPage == nil {
+			return volumes, nil
+		}
+		request.Page = res.OpcNextPage
+	}
+}
+
+// ListVolumes returns the block volumes of a compartment.
+func (d *driverOCI) ListVolumes(ctx context.Context, compartmentId string) ([]core.Volume, error) {
+	var volumes []core.Volume
+	request := core.ListVolumesRequest{CompartmentId: &compartmentId}
+	for {
+		res, err := d.blockstorageClient.ListVolumes(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, res.Items...)
+		if res.OpcNextPage == nil {
+			return volumes, nil
+		}
+		request.Page = res.OpcNextPage
+	}
+}
+
+// ListVolumeAttachments returns the volume attachments of a compartment.
+func (d *driverOCI) ListVolumeAttachments(ctx context.Context, compartmentId string) ([]core.VolumeAttachment, error) {
+	var attachments []core.VolumeAttachment
+	request := core.ListVolumeAttachmentsRequest{CompartmentId: &compartmentId}
+	for {
+		res, err := d.computeClient.ListVolumeAttachments(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, res.Items...)
+		if res.OpcNextPage == nil {
+			return attachments, nil
+		}
+		request.Page = res.OpcNextPage
+	}
+}
+
+// ListInstanceVolumeAttachments returns the volume attachments of an
+// instance.
+func (d *driverOCI) ListInstanceVolumeAttachments(ctx context.Context, instanceId string) ([]core.VolumeAttachment, error) {
+	res, err := d.computeClient.ListVolumeAttachments(ctx, core.ListVolumeAttachmentsRequest{
+		CompartmentId: &d.cfg.CompartmentID,
+		InstanceId:    &instanceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
 
 // WaitForImageCreation waits for a provisioning custom image to reach the
 // "AVAILABLE" state.
 func (d *driverOCI) WaitForImageCreation(ctx context.Context, id string) error {
+	return waitForImageCreation(ctx, d.computeClient, id)
+}
+
+// WaitForRegionalImageCreation waits for a provisioning or importing custom
+// image of region to reach the "AVAILABLE" state.
+func (d *driverOCI) WaitForRegionalImageCreation(ctx context.Context, region string, id string) error {
+	client, err := d.regionalComputeClient(region)
+	if err != nil {
+		return err
+	}
+	return waitForImageCreation(ctx, client, id)
+}
+
+// waitForImageCreation waits for an image of the region of client to reach
+// the "AVAILABLE" state.
+func waitForImageCreation(ctx context.Context, client core.ComputeClient, id string) error {
 	return waitForResourceToReachState(
 		func(string) (string, error) {
-			image, err := d.computeClient.GetImage(ctx, core.GetImageRequest{ImageId: &id})
+			image, err := client.GetImage(ctx, core.GetImageRequest{ImageId: &id})
 			if err != nil {
 				return "", err
 			}
 			return string(image.LifecycleState), nil
 		},
 		id,
-		[]string{"PROVISIONING"},
+		[]string{"PROVISIONING", "IMPORTING"},
 		"AVAILABLE",
 		0,             //Unlimited Retries
 		5*time.Second, //5 second wait between retries
 	)
 }
 
+// WaitForWorkRequest waits for a work request to succeed.
+func (d *driverOCI) WaitForWorkRequest(ctx context.Context, id string) error {
+	return waitForResourceToReachState(
+		func(string) (string, error) {
+			workRequest, err := d.workRequestClient.GetWorkRequest(ctx, workrequests.GetWorkRequestRequest{WorkRequestId: &id})
+			if err != nil {
+				return "", err
+			}
+			return string(workRequest.Status), nil
+		},
+		id,
+		[]string{"ACCEPTED", "IN_PROGRESS"},
+		"SUCCEEDED",
+		0,              //Unlimited Retries
+		10*time.Second, //10 second wait between retries
+	)
+}
+
 // WaitForInstanceState waits for an instance to reach the a given terminal
 // state.
 func (d *driverOCI) WaitForInstanceState(ctx context.Context, id string, waitStates []string, terminalState string) error {
@@ -310,6 +943,25 @@ func (d *driverOCI) WaitForBootVolumeState(ctx context.Context, id string, waitS
 	)
 }
 
+// WaitForVolumeState waits for a block volume to reach the given terminal
+// state.
+func (d *driverOCI) WaitForVolumeState(ctx context.Context, id string, waitStates []string, terminalState string) error {
+	return waitForResourceToReachState(
+		func(string) (string, error) {
+			volume, err := d.blockstorageClient.GetVolume(ctx, core.GetVolumeRequest{VolumeId: &id})
+			if err != nil {
+				return "", err
+			}
+			return string(volume.LifecycleState), nil
+		},
+		id,
+		waitStates,
+		terminalState,
+		0,             //Unlimited Retries
+		5*time.Second, //5 second wait between retries
+	)
+}
+
 // WaitForVolumeAttachmentState waits for a Volume Attachment to reach the given terminal
 // state.
 func (d *driverOCI) WaitForVolumeAttachmentState(ctx context.Context, id string, waitStates []string, terminalState string) error {
@@ -0,0 +1,27 @@
+//go:generate mapstructure-to-hcl2 -type ImageCopyTarget
+
+package ocisurrogate
+
+import (
+	"fmt"
+)
+
+// ImageCopyTarget is a compartment the image is copied to. Like the copies
+// of copy_to_regions, the copy is imported from the Object Storage export of
+// the image.
+type ImageCopyTarget struct {
+	// CompartmentID is the compartment the copy is imported in.
+	CompartmentID string `mapstructure:"compartment_ocid"`
+	// Region the copy is imported in. Defaults to the region of the build.
+	Region string `mapstructure:"region"`
+}
+
+func (t *ImageCopyTarget) Prepare(index int) []error {
+	var errs []error
+
+	if t.CompartmentID == "" {
+		errs = append(errs, fmt.Errorf("image_copy_targets[%d]: compartment_ocid must be specified", index))
+	}
+
+	return errs
+}
@@ -0,0 +1,17 @@
+package ocisurrogate
+
+import (
+	"testing"
+)
+
+func TestImageCopyTargetPrepare(t *testing.T) {
+	target := ImageCopyTarget{CompartmentID: "ocid1.compartment"}
+	if errs := target.Prepare(0); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	target = ImageCopyTarget{Region: "us-phoenix-1"}
+	if errs := target.Prepare(1); len(errs) != 1 {
+		t.Fatalf("expected an error without compartment_ocid, got %v", errs)
+	}
+}
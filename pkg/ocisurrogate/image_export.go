@@ -0,0 +1,109 @@
+//go:generate mapstructure-to-hcl2 -type ImageExport
+
+package ocisurrogate
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/packer/template/interpolate"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+)
+
+// ImageExport exports the image to an Object Storage bucket once it is
+// created.
+type ImageExport struct {
+	Bucket    string `mapstructure:"bucket"`
+	Namespace string `mapstructure:"namespace"`
+	// ObjectName is the name of the object, rendered with the variables
+	// {{.ImageName}} and {{.ImageOCID}}. Defaults to
+	// {{.ImageName}}.<format>.
+	ObjectName string `mapstructure:"object_name"`
+	// Format is QCOW2, VMDK, OCI, VHD or VDI. Defaults to OCI.
+	Format string `mapstructure:"format"`
+	// PublishDuration publishes the exported object through a
+	// pre-authenticated request valid for that long, e.g. 720h, so that
+	// other tenancies can import it.
+	PublishDuration time.Duration `mapstructure:"publish_duration"`
+}
+
+// imageExportTemplate is the interpolation data of export.object_name.
+type imageExportTemplate struct {
+	ImageName string
+	ImageOCID string
+}
+
+func (e *ImageExport) Prepare(ctx *interpolate.Context) []error {
+	var errs []error
+
+	if e.Bucket == "" && e.Namespace == "" && e.ObjectName == "" && e.Format == "" && e.PublishDuration == 0 {
+		return nil
+	}
+
+	for _, field := range []*string{&e.Bucket, &e.Namespace} {
+		rendered, err := interpolate.Render(*field, ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("export: %s", err))
+		}
+		*field = rendered
+	}
+	if e.Bucket == "" {
+		errs = append(errs, errors.New("export: bucket must be specified"))
+	}
+	if e.Namespace == "" {
+		errs = append(errs, errors.New("export: namespace must be specified"))
+	}
+	switch e.Format {
+	case "", "QCOW2", "VMDK", "OCI", "VHD", "VDI":
+	default:
+		errs = append(errs, fmt.Errorf("export: format must be one of QCOW2, VMDK, OCI, VHD or VDI, got %q", e.Format))
+	}
+	if e.PublishDuration < 0 {
+		errs = append(errs, fmt.Errorf("export: publish_duration must be positive, got %s", e.PublishDuration))
+	}
+	if e.ObjectName == "" {
+		format := e.Format
+		if format == "" {
+			format = "OCI"
+		}
+		e.ObjectName = "{{.ImageName}}." + strings.ToLower(format)
+	}
+	if err := interpolate.Validate(e.ObjectName, ctx); err != nil {
+		errs = append(errs, fmt.Errorf("export: invalid object_name: %s", err))
+	}
+
+	return errs
+}
+
+// isSet reports whether the image is exported.
+func (e *ImageExport) isSet() bool {
+	return e.Bucket != ""
+}
+
+// objectName renders the object name of the image.
+func (e *ImageExport) objectName(ctx interpolate.Context, imageName string, imageID string) (string, error) {
+	ctx.Data = &imageExportTemplate{ImageName: imageName, ImageOCID: imageID}
+	return interpolate.Render(e.ObjectName, &ctx)
+}
+
+// objectURL returns the URL of an object of the bucket in region, on the
+// Object Storage endpoint of the realm of the region.
+func (e *ImageExport) objectURL(region string, objectName string) string {
+	return fmt.Sprintf("%s/n/%s/b/%s/o/%s",
+		objectStorageEndpoint(region), url.PathEscape(e.Namespace), url.PathEscape(e.Bucket), url.PathEscape(objectName))
+}
+
+// preauthenticatedURL returns the URL of the pre-authenticated request with
+// the given access URI in region.
+func preauthenticatedURL(region string, accessURI string) string {
+	return objectStorageEndpoint(region) + accessURI
+}
+
+// objectStorageEndpoint returns the Object Storage endpoint of region, in
+// the realm of the region.
+func objectStorageEndpoint(region string) string {
+	return ocicommon.StringToRegion(region).EndpointForTemplate("objectstorage", "https://objectstorage.{region}.{secondLevelDomain}")
+}
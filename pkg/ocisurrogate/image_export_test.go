@@ -0,0 +1,57 @@
+package ocisurrogate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/packer/template/interpolate"
+)
+
+func TestImageExportPrepare(t *testing.T) {
+	ctx := &interpolate.Context{}
+	e := ImageExport{Bucket: "images", Namespace: "ns", Format: "QCOW2"}
+	if errs := e.Prepare(ctx); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	name, err := e.objectName(*ctx, "HelloWorld", "ocid1.image")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if name != "HelloWorld.qcow2" {
+		t.Errorf("bad default object name: %s", name)
+	}
+
+	expected := "https://objectstorage.us-ashburn-1.oraclecloud.com/n/ns/b/images/o/HelloWorld.qcow2"
+	if url := e.objectURL("us-ashburn-1", name); url != expected {
+		t.Errorf("bad object URL: %s", url)
+	}
+
+	expected = "https://objectstorage.us-langley-1.oraclegovcloud.com/n/ns/b/images/o/HelloWorld.qcow2"
+	if url := e.objectURL("us-langley-1", name); url != expected {
+		t.Errorf("bad object URL in the OC2 realm: %s", url)
+	}
+}
+
+func TestPreauthenticatedURL(t *testing.T) {
+	expected := "https://objectstorage.us-ashburn-1.oraclecloud.com/p/secret/n/ns/b/images/o/HelloWorld.oci"
+	if url := preauthenticatedURL("us-ashburn-1", "/p/secret/n/ns/b/images/o/HelloWorld.oci"); url != expected {
+		t.Errorf("bad pre-authenticated request URL: %s", url)
+	}
+}
+
+func TestImageExportPrepare_Unset(t *testing.T) {
+	var e ImageExport
+	if errs := e.Prepare(&interpolate.Context{}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if e.isSet() {
+		t.Fatalf("export should not be set")
+	}
+}
+
+func TestImageExportPrepare_Invalid(t *testing.T) {
+	e := ImageExport{ObjectName: "{{.ImageName", Format: "RAW"}
+	if errs := e.Prepare(&interpolate.Context{}); len(errs) != 4 {
+		t.Fatalf("expected 4 errors, got %v", errs)
+	}
+}
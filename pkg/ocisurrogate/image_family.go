@@ -0,0 +1,109 @@
+//go:generate mapstructure-to-hcl2 -type ImageFamily
+
+package ocisurrogate
+
+import (
+	"errors"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// ImageFamily groups the images of successive builds: the image gets the
+// freeform tag TagKey set to Name, and the previous newest image of the
+// family the defined tag DeprecatedTagNamespace.DeprecatedTagKey set to
+// "true", so the newest image of a family is its only member not
+// deprecated.
+type ImageFamily struct {
+	// Name of the family.
+	Name string `mapstructure:"name"`
+	// TagKey is the freeform tag naming the family of an image. Defaults to
+	// family.
+	TagKey string `mapstructure:"tag_key"`
+	// DeprecatedTagNamespace and DeprecatedTagKey are the defined tag
+	// deprecating the previous image. The key defaults to deprecated.
+	DeprecatedTagNamespace string `mapstructure:"deprecated_tag_namespace"`
+	DeprecatedTagKey       string `mapstructure:"deprecated_tag_key"`
+	// CompartmentID is the compartment the family is looked up in.
+	// Defaults to image_move_to_compartment, or compartment_ocid.
+	CompartmentID string `mapstructure:"compartment_ocid"`
+}
+
+func (f *ImageFamily) Prepare(compartmentID string) []error {
+	var errs []error
+
+	if !f.isSet() {
+		return errs
+	}
+
+	if f.Name == "" {
+		errs = append(errs, errors.New("image_family: name must be specified"))
+	}
+	if f.TagKey == "" {
+		f.TagKey = "family"
+	}
+	if f.DeprecatedTagNamespace == "" {
+		errs = append(errs, errors.New("image_family: deprecated_tag_namespace must be specified"))
+	}
+	if f.DeprecatedTagKey == "" {
+		f.DeprecatedTagKey = "deprecated"
+	}
+	if f.CompartmentID == "" {
+		f.CompartmentID = compartmentID
+	}
+
+	return errs
+}
+
+// isSet reports whether the image belongs to a family.
+func (f *ImageFamily) isSet() bool {
+	return f.Name != "" || f.TagKey != "" || f.DeprecatedTagNamespace != "" ||
+		f.DeprecatedTagKey != "" || f.CompartmentID != ""
+}
+
+// imageTags returns the freeform tags of the image, tags with the family
+// tag.
+func (f *ImageFamily) imageTags(tags map[string]string) map[string]string {
+	if !f.isSet() {
+		return tags
+	}
+	imageTags := map[string]string{f.TagKey: f.Name}
+	for k, v := range tags {
+		if k != f.TagKey {
+			imageTags[k] = v
+		}
+	}
+	return imageTags
+}
+
+// filter returns the filter listing the images of the compartment.
+func (f *ImageFamily) filter() *ImageFilter {
+	return &ImageFilter{CompartmentID: f.CompartmentID}
+}
+
+// previous returns the newest image of the family among images, listed
+// newest first, other than the image imageID.
+func (f *ImageFamily) previous(images []core.Image, imageID string) (core.Image, bool) {
+	for _, image := range images {
+		if image.Id == nil || *image.Id == imageID || image.FreeformTags[f.TagKey] != f.Name {
+			continue
+		}
+		return image, true
+	}
+	return core.Image{}, false
+}
+
+// deprecatedTags returns the defined tags of image deprecated.
+func (f *ImageFamily) deprecatedTags(image core.Image) map[string]map[string]interface{} {
+	tags := map[string]map[string]interface{}{}
+	for namespace, keys := range image.DefinedTags {
+		tags[namespace] = map[string]interface{}{}
+		for k, v := range keys {
+			tags[namespace][k] = v
+		}
+	}
+	if tags[f.DeprecatedTagNamespace] == nil {
+		tags[f.DeprecatedTagNamespace] = map[string]interface{}{}
+	}
+	tags[f.DeprecatedTagNamespace][f.DeprecatedTagKey] = "true"
+	return tags
+}
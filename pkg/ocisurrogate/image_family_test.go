@@ -0,0 +1,102 @@
+package ocisurrogate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func testFamilyImages(now time.Time) []core.Image {
+	images := []core.Image{
+		testImage("ocid1.image.new", "ol8-base-3", now),
+		testImage("ocid1.image.other", "ol7-base-2", now.Add(-time.Hour)),
+		testImage("ocid1.image.2", "ol8-base-2", now.Add(-24*time.Hour)),
+		testImage("ocid1.image.1", "ol8-base-1", now.Add(-48*time.Hour)),
+	}
+	images[0].FreeformTags = map[string]string{"family": "ol8-base"}
+	images[1].FreeformTags = map[string]string{"family": "ol7-base"}
+	images[2].FreeformTags = map[string]string{"family": "ol8-base"}
+	images[2].DefinedTags = map[string]map[string]interface{}{"Operations": {"CostCenter": "42"}}
+	images[3].FreeformTags = map[string]string{"family": "ol8-base"}
+	return images
+}
+
+func TestImageFamilyPrepare(t *testing.T) {
+	f := ImageFamily{Name: "ol8-base", DeprecatedTagNamespace: "Images"}
+	if errs := f.Prepare("ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if f.TagKey != "family" || f.DeprecatedTagKey != "deprecated" {
+		t.Errorf("bad default tag keys: %q, %q", f.TagKey, f.DeprecatedTagKey)
+	}
+	if f.CompartmentID != "ocid1.compartment" {
+		t.Errorf("compartment_ocid should default to the build compartment, got %q", f.CompartmentID)
+	}
+
+	f = ImageFamily{TagKey: "family"}
+	errs := f.Prepare("ocid1.compartment")
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "name") || !strings.Contains(errs[1].Error(), "deprecated_tag_namespace") {
+		t.Errorf("bad errors: %v", errs)
+	}
+}
+
+func TestImageFamilyPrepare_Unset(t *testing.T) {
+	var f ImageFamily
+	if errs := f.Prepare("ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if f.isSet() {
+		t.Fatalf("image_family should not be set")
+	}
+
+	tags := map[string]string{"team": "platform"}
+	if imageTags := f.imageTags(tags); !reflect.DeepEqual(imageTags, tags) {
+		t.Errorf("should not tag the image, got %v", imageTags)
+	}
+}
+
+func TestImageFamilyImageTags(t *testing.T) {
+	f := ImageFamily{Name: "ol8-base", DeprecatedTagNamespace: "Images"}
+	f.Prepare("ocid1.compartment")
+
+	tags := map[string]string{"team": "platform", "family": "stale"}
+	expected := map[string]string{"team": "platform", "family": "ol8-base"}
+	if imageTags := f.imageTags(tags); !reflect.DeepEqual(imageTags, expected) {
+		t.Errorf("bad image tags: %v", imageTags)
+	}
+	if tags["family"] != "stale" {
+		t.Errorf("should not modify the tags of the build")
+	}
+}
+
+func TestImageFamilyPrevious(t *testing.T) {
+	f := ImageFamily{Name: "ol8-base", DeprecatedTagNamespace: "Images"}
+	f.Prepare("ocid1.compartment")
+	images := testFamilyImages(time.Now())
+
+	previous, ok := f.previous(images, "ocid1.image.new")
+	if !ok || *previous.Id != "ocid1.image.2" {
+		t.Fatalf("bad previous image: %v", previous.Id)
+	}
+
+	expected := map[string]map[string]interface{}{
+		"Operations": {"CostCenter": "42"},
+		"Images":     {"deprecated": "true"},
+	}
+	if tags := f.deprecatedTags(previous); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("bad deprecated tags: %v", tags)
+	}
+	if _, ok := previous.DefinedTags["Images"]; ok {
+		t.Errorf("should not modify the tags of the image")
+	}
+
+	if _, ok := f.previous(images[:2], "ocid1.image.new"); ok {
+		t.Errorf("should not find a previous image")
+	}
+}
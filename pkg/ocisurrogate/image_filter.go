@@ -0,0 +1,110 @@
+//go:generate mapstructure-to-hcl2 -type ImageFilter
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// ImageFilter selects the base image, or the target image of the surrogate
+// named base_surrogate_image_name, among the available images of a
+// compartment.
+type ImageFilter struct {
+	// OperatingSystem and OperatingSystemVersion, e.g. "Oracle Linux" and
+	// "7.8".
+	OperatingSystem        string `mapstructure:"operating_system"`
+	OperatingSystemVersion string `mapstructure:"operating_system_version"`
+	// Shape only matches images compatible with the shape.
+	Shape string `mapstructure:"shape"`
+	// DisplayName matches the display name exactly, DisplayNameRegex as a
+	// regular expression.
+	DisplayName      string `mapstructure:"display_name"`
+	DisplayNameRegex string `mapstructure:"display_name_regex"`
+	// CompartmentID is the compartment the images are listed in. Defaults
+	// to compartment_ocid.
+	CompartmentID string `mapstructure:"compartment_ocid"`
+	// MostRecent selects the newest image when several match, which is
+	// otherwise an error.
+	MostRecent bool `mapstructure:"most_recent"`
+
+	displayNameRegex *regexp.Regexp
+}
+
+func (f *ImageFilter) Prepare(name string, compartmentID string) []error {
+	var errs []error
+
+	if f.CompartmentID == "" {
+		f.CompartmentID = compartmentID
+	}
+	if f.DisplayNameRegex != "" {
+		re, err := regexp.Compile(f.DisplayNameRegex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid display_name_regex: %s", name, err))
+		}
+		f.displayNameRegex = re
+	}
+
+	return errs
+}
+
+// isSet reports whether any criterion is given.
+func (f *ImageFilter) isSet() bool {
+	return f.OperatingSystem != "" || f.OperatingSystemVersion != "" || f.Shape != "" ||
+		f.DisplayName != "" || f.DisplayNameRegex != ""
+}
+
+// listImagesRequest returns the request listing the available images
+// matching the criteria the service filters on.
+func (f *ImageFilter) listImagesRequest() core.ListImagesRequest {
+	request := core.ListImagesRequest{
+		CompartmentId:  &f.CompartmentID,
+		LifecycleState: core.ImageLifecycleStateAvailable,
+		SortBy:         core.ListImagesSortByTimecreated,
+		SortOrder:      core.ListImagesSortOrderDesc,
+	}
+	if f.OperatingSystem != "" {
+		request.OperatingSystem = &f.OperatingSystem
+	}
+	if f.OperatingSystemVersion != "" {
+		request.OperatingSystemVersion = &f.OperatingSystemVersion
+	}
+	if f.Shape != "" {
+		request.Shape = &f.Shape
+	}
+	if f.DisplayName != "" {
+		request.DisplayName = &f.DisplayName
+	}
+	return request
+}
+
+// selectImage returns the image among images matching the filter.
+func (f *ImageFilter) selectImage(images []core.Image) (core.Image, error) {
+	var matches []core.Image
+	for _, image := range images {
+		if f.displayNameRegex != nil && (image.DisplayName == nil || !f.displayNameRegex.MatchString(*image.DisplayName)) {
+			continue
+		}
+		matches = append(matches, image)
+	}
+
+	switch {
+	case len(matches) == 0:
+		return core.Image{}, fmt.Errorf("no available image in compartment %s matches the filter", f.CompartmentID)
+	case len(matches) > 1 && !f.MostRecent:
+		return core.Image{}, fmt.Errorf("%d images match the filter, set most_recent to use the newest", len(matches))
+	}
+
+	// Newest first, images without a creation time last.
+	sort.SliceStable(matches, func(i, j int) bool {
+		ti, tj := matches[i].TimeCreated, matches[j].TimeCreated
+		if ti == nil || tj == nil {
+			return ti != nil && tj == nil
+		}
+		return ti.After(tj.Time)
+	})
+	return matches[0], nil
+}
@@ -0,0 +1,84 @@
+package ocisurrogate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func testImage(id string, name string, created time.Time) core.Image {
+	return core.Image{Id: &id, DisplayName: &name, TimeCreated: &common.SDKTime{Time: created}}
+}
+
+func TestImageFilterSelectImage(t *testing.T) {
+	now := time.Now()
+	images := []core.Image{
+		testImage("ocid1.image.old", "Oracle-Linux-7.8-2020.05.01-0", now.Add(-time.Hour)),
+		testImage("ocid1.image.new", "Oracle-Linux-7.8-2020.06.01-0", now),
+		testImage("ocid1.image.gpu", "Oracle-Linux-7.8-Gen2-GPU-2020.06.01-0", now),
+	}
+
+	f := ImageFilter{DisplayNameRegex: `^Oracle-Linux-7\.8-\d`, MostRecent: true}
+	if errs := f.Prepare("base_image_filter", "ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if f.CompartmentID != "ocid1.compartment" {
+		t.Errorf("compartment_ocid should default to the build compartment, got %q", f.CompartmentID)
+	}
+
+	image, err := f.selectImage(images)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *image.Id != "ocid1.image.new" {
+		t.Errorf("should have selected the newest match, got %s", *image.Id)
+	}
+
+	f.MostRecent = false
+	if _, err := f.selectImage(images); err == nil || !strings.Contains(err.Error(), "most_recent") {
+		t.Errorf("expected an error about several matches, got %v", err)
+	}
+}
+
+func TestImageFilterSelectImage_NoMatch(t *testing.T) {
+	f := ImageFilter{OperatingSystem: "Canonical Ubuntu"}
+	f.Prepare("base_image_filter", "ocid1.compartment")
+
+	if _, err := f.selectImage(nil); err == nil || !strings.Contains(err.Error(), "no available image") {
+		t.Errorf("expected an error about no match, got %v", err)
+	}
+}
+
+func TestImageFilterPrepare_InvalidRegex(t *testing.T) {
+	f := ImageFilter{DisplayNameRegex: "("}
+	errs := f.Prepare("base_image_filter", "ocid1.compartment")
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "base_image_filter: ") {
+		t.Fatalf("expected 1 base_image_filter error, got %v", errs)
+	}
+}
+
+func TestImageFilterSelectImage_NoTimeCreated(t *testing.T) {
+	now := time.Now()
+	undated := testImage("ocid1.image.undated", "Oracle-Linux-7.8-2020.04.01-0", now)
+	undated.TimeCreated = nil
+	images := []core.Image{
+		undated,
+		testImage("ocid1.image.old", "Oracle-Linux-7.8-2020.05.01-0", now.Add(-time.Hour)),
+		undated,
+		testImage("ocid1.image.new", "Oracle-Linux-7.8-2020.06.01-0", now),
+	}
+
+	f := ImageFilter{MostRecent: true}
+	f.Prepare("base_image_filter", "ocid1.compartment")
+
+	image, err := f.selectImage(images)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if *image.Id != "ocid1.image.new" {
+		t.Errorf("should have selected the newest dated image, got %s", *image.Id)
+	}
+}
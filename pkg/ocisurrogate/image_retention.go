@@ -0,0 +1,107 @@
+//go:generate mapstructure-to-hcl2 -type ImageRetention
+
+package ocisurrogate
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// ImageRetention prunes the images of older builds once the image is
+// created. The images of the builds are the available images of the
+// compartment whose display name starts with NamePrefix and with all the
+// freeform Tags. The new image is always kept.
+type ImageRetention struct {
+	// NamePrefix and Tags select the images of the builds. At least one of
+	// them must be set.
+	NamePrefix string            `mapstructure:"name_prefix"`
+	Tags       map[string]string `mapstructure:"tags"`
+	// CompartmentID is the compartment the images are listed in. Defaults
+	// to image_move_to_compartment, or compartment_ocid.
+	CompartmentID string `mapstructure:"compartment_ocid"`
+	// KeepLast keeps the newest images, the new one included, and MaxAge
+	// the images younger than it. When both are set, an image is deleted
+	// when it is neither.
+	KeepLast int           `mapstructure:"keep_last"`
+	MaxAge   time.Duration `mapstructure:"max_age"`
+	// DryRun only lists the images that would be deleted.
+	DryRun bool `mapstructure:"dry_run"`
+}
+
+func (r *ImageRetention) Prepare(compartmentID string) []error {
+	var errs []error
+
+	if !r.isSet() {
+		return errs
+	}
+
+	if r.CompartmentID == "" {
+		r.CompartmentID = compartmentID
+	}
+	if r.NamePrefix == "" && len(r.Tags) == 0 {
+		errs = append(errs, errors.New("retention: name_prefix or tags must be specified"))
+	}
+	if r.KeepLast < 0 {
+		errs = append(errs, errors.New("retention: keep_last must not be negative"))
+	}
+	if r.MaxAge < 0 {
+		errs = append(errs, errors.New("retention: max_age must not be negative"))
+	}
+	if r.KeepLast == 0 && r.MaxAge == 0 {
+		errs = append(errs, errors.New("retention: keep_last or max_age must be specified"))
+	}
+
+	return errs
+}
+
+// isSet reports whether older images are pruned.
+func (r *ImageRetention) isSet() bool {
+	return r.NamePrefix != "" || len(r.Tags) != 0 || r.CompartmentID != "" ||
+		r.KeepLast != 0 || r.MaxAge != 0 || r.DryRun
+}
+
+// filter returns the filter listing the images of the compartment.
+func (r *ImageRetention) filter() *ImageFilter {
+	return &ImageFilter{CompartmentID: r.CompartmentID}
+}
+
+// matches reports whether image is one of the images of the builds.
+func (r *ImageRetention) matches(image core.Image) bool {
+	if image.DisplayName == nil || !strings.HasPrefix(*image.DisplayName, r.NamePrefix) {
+		return false
+	}
+	for k, v := range r.Tags {
+		if value, ok := image.FreeformTags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// expired returns the images among images, listed newest first, to delete
+// at now, keeping the new image keep.
+func (r *ImageRetention) expired(images []core.Image, keep core.Image, now time.Time) []core.Image {
+	var expired []core.Image
+	// the new image is the newest kept if it is one of the pruned images
+	kept := 0
+	if r.matches(keep) {
+		kept = 1
+	}
+	for _, image := range images {
+		if image.Id == nil || *image.Id == *keep.Id || !r.matches(image) {
+			continue
+		}
+		if kept < r.KeepLast {
+			kept++
+			continue
+		}
+		if r.MaxAge != 0 && (image.TimeCreated == nil || now.Sub(image.TimeCreated.Time) <= r.MaxAge) {
+			continue
+		}
+		expired = append(expired, image)
+	}
+	return expired
+}
@@ -0,0 +1,120 @@
+package ocisurrogate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func testRetentionImages(now time.Time) []core.Image {
+	images := []core.Image{
+		testImage("ocid1.image.new", "nightly-5", now),
+		testImage("ocid1.image.4", "nightly-4", now.Add(-24*time.Hour)),
+		testImage("ocid1.image.other", "other-4", now.Add(-24*time.Hour)),
+		testImage("ocid1.image.3", "nightly-3", now.Add(-48*time.Hour)),
+		testImage("ocid1.image.2", "nightly-2", now.Add(-72*time.Hour)),
+		testImage("ocid1.image.1", "nightly-1", now.Add(-96*time.Hour)),
+	}
+	for i := range images {
+		images[i].FreeformTags = map[string]string{"pipeline": "nightly"}
+	}
+	images[2].FreeformTags = map[string]string{"pipeline": "release"}
+	return images
+}
+
+func imageIDs(images []core.Image) []string {
+	var ids []string
+	for _, image := range images {
+		ids = append(ids, *image.Id)
+	}
+	return ids
+}
+
+func TestImageRetentionPrepare(t *testing.T) {
+	r := ImageRetention{NamePrefix: "nightly-", KeepLast: 3}
+	if errs := r.Prepare("ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if r.CompartmentID != "ocid1.compartment" {
+		t.Errorf("compartment_ocid should default to the build compartment, got %q", r.CompartmentID)
+	}
+
+	r = ImageRetention{KeepLast: -1}
+	errs := r.Prepare("ocid1.compartment")
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "name_prefix or tags") || !strings.Contains(errs[1].Error(), "keep_last") {
+		t.Errorf("bad errors: %v", errs)
+	}
+
+	r = ImageRetention{NamePrefix: "nightly-"}
+	if errs := r.Prepare("ocid1.compartment"); len(errs) != 1 || !strings.Contains(errs[0].Error(), "keep_last or max_age") {
+		t.Errorf("expected a keep_last or max_age error, got %v", errs)
+	}
+}
+
+func TestImageRetentionPrepare_Unset(t *testing.T) {
+	var r ImageRetention
+	if errs := r.Prepare("ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if r.isSet() {
+		t.Fatalf("retention should not be set")
+	}
+}
+
+func TestImageRetentionExpired(t *testing.T) {
+	now := time.Now()
+	images := testRetentionImages(now)
+
+	cases := []struct {
+		name      string
+		retention ImageRetention
+		expected  []string
+	}{
+		{
+			name:      "KeepLast",
+			retention: ImageRetention{NamePrefix: "nightly-", KeepLast: 3},
+			expected:  []string{"ocid1.image.2", "ocid1.image.1"},
+		},
+		{
+			name:      "MaxAge",
+			retention: ImageRetention{Tags: map[string]string{"pipeline": "nightly"}, MaxAge: 60 * time.Hour},
+			expected:  []string{"ocid1.image.2", "ocid1.image.1"},
+		},
+		{
+			name:      "KeepLastAndMaxAge",
+			retention: ImageRetention{NamePrefix: "nightly-", KeepLast: 4, MaxAge: 60 * time.Hour},
+			expected:  []string{"ocid1.image.1"},
+		},
+		{
+			name:      "KeepLastOne",
+			retention: ImageRetention{Tags: map[string]string{"pipeline": "nightly"}, KeepLast: 1},
+			expected:  []string{"ocid1.image.4", "ocid1.image.3", "ocid1.image.2", "ocid1.image.1"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expired := tc.retention.expired(images, images[0], now)
+			if ids := imageIDs(expired); !reflect.DeepEqual(ids, tc.expected) {
+				t.Errorf("bad expired images: %v, expected %v", ids, tc.expected)
+			}
+		})
+	}
+}
+
+func TestImageRetentionExpired_NewImageNotMatching(t *testing.T) {
+	now := time.Now()
+	images := testRetentionImages(now)
+	newImage := testImage("ocid1.image.release", "release-1", now)
+
+	r := ImageRetention{NamePrefix: "nightly-", KeepLast: 2}
+	expected := []string{"ocid1.image.3", "ocid1.image.2", "ocid1.image.1"}
+	if ids := imageIDs(r.expired(images, newImage, now)); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("should keep the last 2 matching images besides the new one: %v, expected %v", ids, expected)
+	}
+}
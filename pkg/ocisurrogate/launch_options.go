@@ -0,0 +1,142 @@
+//go:generate mapstructure-to-hcl2 -type LaunchOptions
+
+package ocisurrogate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// LaunchOptions are the launch options and platform configuration of the
+// surrogate instance. The image is created in launch_mode, and firmware,
+// boot_volume_type and network_type are set as its default capabilities, so
+// that it boots with the settings it was verified on.
+type LaunchOptions struct {
+	// LaunchMode is the launch mode of the image: NATIVE, EMULATED,
+	// PARAVIRTUALIZED or CUSTOM. Defaults to CUSTOM when any launch option is
+	// set.
+	LaunchMode string `mapstructure:"launch_mode"`
+	// Firmware is BIOS or UEFI_64. Defaults to the bootloader firmware.
+	Firmware string `mapstructure:"firmware"`
+	// BootVolumeType is ISCSI, SCSI, IDE, VFIO or PARAVIRTUALIZED.
+	BootVolumeType string `mapstructure:"boot_volume_type"`
+	// NetworkType is E1000, VFIO or PARAVIRTUALIZED.
+	NetworkType string `mapstructure:"network_type"`
+	// PlatformType is the platform configuration type of the shape: AMD_VM,
+	// INTEL_VM, AMD_MILAN_BM, AMD_ROME_BM or INTEL_SKYLAKE_BM. It is
+	// required by SecureBoot, MeasuredBoot and TrustedPlatformModule.
+	PlatformType          string `mapstructure:"platform_type"`
+	SecureBoot            bool   `mapstructure:"secure_boot"`
+	MeasuredBoot          bool   `mapstructure:"measured_boot"`
+	TrustedPlatformModule bool   `mapstructure:"trusted_platform_module"`
+}
+
+func (o *LaunchOptions) Prepare(bootloader *Bootloader) []error {
+	var errs []error
+
+	if o.Firmware == "" {
+		switch bootloader.Firmware {
+		case "uefi":
+			o.Firmware = string(core.LaunchOptionsFirmwareUefi64)
+		case "bios":
+			o.Firmware = string(core.LaunchOptionsFirmwareBios)
+		}
+	}
+	if o.LaunchMode == "" && o.isSet() {
+		o.LaunchMode = string(core.CreateImageDetailsLaunchModeCustom)
+	}
+
+	switch o.LaunchMode {
+	case "", "NATIVE", "EMULATED", "PARAVIRTUALIZED", "CUSTOM":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"launch_options: launch_mode must be one of NATIVE, EMULATED, PARAVIRTUALIZED or CUSTOM, got %q", o.LaunchMode))
+	}
+	switch o.Firmware {
+	case "", "BIOS", "UEFI_64":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"launch_options: firmware must be one of BIOS or UEFI_64, got %q", o.Firmware))
+	}
+	switch o.BootVolumeType {
+	case "", "ISCSI", "SCSI", "IDE", "VFIO", "PARAVIRTUALIZED":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"launch_options: boot_volume_type must be one of ISCSI, SCSI, IDE, VFIO or PARAVIRTUALIZED, got %q", o.BootVolumeType))
+	}
+	switch o.NetworkType {
+	case "", "E1000", "VFIO", "PARAVIRTUALIZED":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"launch_options: network_type must be one of E1000, VFIO or PARAVIRTUALIZED, got %q", o.NetworkType))
+	}
+	switch o.PlatformType {
+	case "", "AMD_VM", "INTEL_VM", "AMD_MILAN_BM", "AMD_ROME_BM", "INTEL_SKYLAKE_BM":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"launch_options: platform_type must be one of AMD_VM, INTEL_VM, AMD_MILAN_BM, AMD_ROME_BM or INTEL_SKYLAKE_BM, got %q", o.PlatformType))
+	}
+	if (o.SecureBoot || o.MeasuredBoot || o.TrustedPlatformModule) && o.PlatformType == "" {
+		errs = append(errs, errors.New("launch_options: secure_boot, measured_boot and trusted_platform_module require platform_type"))
+	}
+	if o.SecureBoot && o.Firmware != string(core.LaunchOptionsFirmwareUefi64) {
+		errs = append(errs, errors.New("launch_options: secure_boot requires firmware UEFI_64"))
+	}
+
+	return errs
+}
+
+// isSet reports whether any launch option or platform setting is given.
+func (o *LaunchOptions) isSet() bool {
+	return o.Firmware != "" || o.BootVolumeType != "" || o.NetworkType != "" || o.PlatformType != ""
+}
+
+// launchOptions returns the launch options of a launch request, nil when
+// none are set.
+func (o *LaunchOptions) launchOptions() *core.LaunchOptions {
+	if o.Firmware == "" && o.BootVolumeType == "" && o.NetworkType == "" {
+		return nil
+	}
+	return &core.LaunchOptions{
+		Firmware:       core.LaunchOptionsFirmwareEnum(o.Firmware),
+		BootVolumeType: core.LaunchOptionsBootVolumeTypeEnum(o.BootVolumeType),
+		NetworkType:    core.LaunchOptionsNetworkTypeEnum(o.NetworkType),
+	}
+}
+
+// platformConfig returns the platform configuration of a launch request,
+// nil when platform_type is not set.
+func (o *LaunchOptions) platformConfig() core.LaunchInstancePlatformConfig {
+	secureBoot, measuredBoot, tpm := &o.SecureBoot, &o.MeasuredBoot, &o.TrustedPlatformModule
+	switch o.PlatformType {
+	case "AMD_VM":
+		return core.AmdVmLaunchInstancePlatformConfig{IsSecureBootEnabled: secureBoot, IsMeasuredBootEnabled: measuredBoot, IsTrustedPlatformModuleEnabled: tpm}
+	case "INTEL_VM":
+		return core.IntelVmLaunchInstancePlatformConfig{IsSecureBootEnabled: secureBoot, IsMeasuredBootEnabled: measuredBoot, IsTrustedPlatformModuleEnabled: tpm}
+	case "AMD_MILAN_BM":
+		return core.AmdMilanBmLaunchInstancePlatformConfig{IsSecureBootEnabled: secureBoot, IsMeasuredBootEnabled: measuredBoot, IsTrustedPlatformModuleEnabled: tpm}
+	case "AMD_ROME_BM":
+		return core.AmdRomeBmLaunchInstancePlatformConfig{IsSecureBootEnabled: secureBoot, IsMeasuredBootEnabled: measuredBoot, IsTrustedPlatformModuleEnabled: tpm}
+	case "INTEL_SKYLAKE_BM":
+		return core.IntelSkylakeBmLaunchInstancePlatformConfig{IsSecureBootEnabled: secureBoot, IsMeasuredBootEnabled: measuredBoot, IsTrustedPlatformModuleEnabled: tpm}
+	}
+	return nil
+}
+
+// imageCapabilities returns the image capabilities matching the launch
+// options, each with the launch option as its only, and default, value.
+func (o *LaunchOptions) imageCapabilities() map[string][]string {
+	capabilities := map[string][]string{}
+	if o.Firmware != "" {
+		capabilities["Compute.Firmware"] = []string{o.Firmware}
+	}
+	if o.BootVolumeType != "" {
+		capabilities["Storage.BootVolumeType"] = []string{o.BootVolumeType}
+	}
+	if o.NetworkType != "" {
+		capabilities["Network.AttachmentType"] = []string{o.NetworkType}
+	}
+	return capabilities
+}
@@ -0,0 +1,59 @@
+package ocisurrogate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestLaunchOptionsPrepare(t *testing.T) {
+	o := LaunchOptions{BootVolumeType: "PARAVIRTUALIZED", PlatformType: "AMD_VM", SecureBoot: true}
+	if errs := o.Prepare(&Bootloader{Firmware: "uefi"}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if o.Firmware != "UEFI_64" {
+		t.Errorf("firmware should default to the bootloader firmware, got %q", o.Firmware)
+	}
+	if o.LaunchMode != "CUSTOM" {
+		t.Errorf("launch_mode should default to CUSTOM, got %q", o.LaunchMode)
+	}
+	if options := o.launchOptions(); options == nil || options.BootVolumeType != "PARAVIRTUALIZED" {
+		t.Errorf("bad launch options: %+v", options)
+	}
+	if config, ok := o.platformConfig().(core.AmdVmLaunchInstancePlatformConfig); !ok || !*config.IsSecureBootEnabled {
+		t.Errorf("bad platform config: %+v", o.platformConfig())
+	}
+}
+
+func TestLaunchOptionsPrepare_Empty(t *testing.T) {
+	var o LaunchOptions
+	if errs := o.Prepare(&Bootloader{}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if o.LaunchMode != "" {
+		t.Errorf("launch_mode should be left to the image, got %q", o.LaunchMode)
+	}
+	if o.launchOptions() != nil || o.platformConfig() != nil {
+		t.Errorf("should not set launch options or platform config")
+	}
+}
+
+func TestLaunchOptionsPrepare_Invalid(t *testing.T) {
+	o := LaunchOptions{Firmware: "BIOS", NetworkType: "NVME", SecureBoot: true}
+	errs := o.Prepare(&Bootloader{})
+	o = LaunchOptions{PlatformType: "ARM_VM"}
+	errs = append(errs, o.Prepare(&Bootloader{})...)
+
+	for _, expected := range []string{"network_type", "platform_type must be one of", "require platform_type", "requires firmware UEFI_64"} {
+		found := false
+		for _, err := range errs {
+			found = found || strings.Contains(err.Error(), expected)
+		}
+		if !found {
+			t.Errorf("expected an error containing %q, got %v", expected, errs)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:generate mapstructure-to-hcl2 -type RegionCopy
+
+package ocisurrogate
+
+import (
+	"fmt"
+)
+
+// RegionCopy is a region the image is copied to. The copy is imported from
+// the Object Storage export of the image, through a pre-authenticated
+// request that is deleted once every copy is available.
+type RegionCopy struct {
+	// Region is the name of the region, e.g. us-phoenix-1.
+	Region string `mapstructure:"region"`
+	// CompartmentID is the compartment the copy is imported in. Defaults to
+	// compartment_ocid.
+	CompartmentID string `mapstructure:"compartment_ocid"`
+}
+
+func (r *RegionCopy) Prepare(index int, compartmentID string) []error {
+	var errs []error
+
+	if r.Region == "" {
+		errs = append(errs, fmt.Errorf("copy_to_regions[%d]: region must be specified", index))
+	}
+	if r.CompartmentID == "" {
+		r.CompartmentID = compartmentID
+	}
+
+	return errs
+}
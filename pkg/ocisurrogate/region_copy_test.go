@@ -0,0 +1,20 @@
+package ocisurrogate
+
+import (
+	"testing"
+)
+
+func TestRegionCopyPrepare(t *testing.T) {
+	r := RegionCopy{Region: "us-phoenix-1"}
+	if errs := r.Prepare(0, "ocid1.compartment"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if r.CompartmentID != "ocid1.compartment" {
+		t.Errorf("bad compartment_ocid default: %s", r.CompartmentID)
+	}
+
+	r = RegionCopy{}
+	if errs := r.Prepare(1, "ocid1.compartment"); len(errs) != 1 {
+		t.Fatalf("expected an error without region, got %v", errs)
+	}
+}
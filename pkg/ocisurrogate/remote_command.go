@@ -0,0 +1,73 @@
+package ocisurrogate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/packer/common"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// wrappedCommandTemplate is the interpolation data of command_wrapper.
+type wrappedCommandTemplate struct {
+	Command string
+}
+
+// runRemoteCommand runs command on the builder instance through the
+// communicator and returns its standard output. The command is run by
+// /bin/sh, wrapped as a whole with the configured command_wrapper, so it may
+// be a compound shell command.
+func runRemoteCommand(ctx context.Context, state multistep.StateBag, command string) (string, error) {
+	var stdout bytes.Buffer
+	err := streamRemoteCommand(ctx, state, command, &stdout)
+	return stdout.String(), err
+}
+
+// streamRemoteCommand is like runRemoteCommand but writes the standard
+// output of command to stdout as it runs.
+func streamRemoteCommand(ctx context.Context, state multistep.StateBag, command string, stdout io.Writer) error {
+	comm := state.Get("communicator").(packer.Communicator)
+	wrappedCommand := state.Get("wrappedCommand").(common.CommandWrapper)
+
+	command, err := wrappedCommand("/bin/sh -c " + shellQuote(command))
+	if err != nil {
+		return fmt.Errorf("Error wrapping command: %s", err)
+	}
+
+	return runCommand(ctx, comm, command, stdout)
+}
+
+// runCommand runs command as is through comm, writing its standard output to
+// stdout. A non-zero exit status is returned as an error carrying the
+// command's standard error.
+func runCommand(ctx context.Context, comm packer.Communicator, command string, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := &packer.RemoteCmd{
+		Command: command,
+		Stdout:  stdout,
+		Stderr:  &stderr,
+	}
+
+	log.Printf("Executing remote command: %s", command)
+	if err := comm.Start(ctx, cmd); err != nil {
+		return fmt.Errorf("Error running %q: %s", command, err)
+	}
+	cmd.Wait()
+
+	if status := cmd.ExitStatus(); status != 0 {
+		return fmt.Errorf("%q exited with status %d: %s",
+			command, status, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// shellQuote quotes s so that it is passed as a single word to /bin/sh.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
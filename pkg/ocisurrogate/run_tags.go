@@ -0,0 +1,45 @@
+package ocisurrogate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The freeform tags set on every transient resource of a build, on top of
+// run_tags, so that resources left behind by a killed build can be
+// attributed and swept.
+const (
+	runTagBuildName = "packer_build_name"
+	runTagBuildUUID = "packer_build_uuid"
+)
+
+// runTags returns the freeform tags of the transient resources of the build
+// buildUUID named buildName.
+func runTags(tags map[string]string, buildName string, buildUUID string) map[string]string {
+	runTags := map[string]string{runTagBuildUUID: buildUUID}
+	if buildName != "" {
+		runTags[runTagBuildName] = buildName
+	}
+	for k, v := range tags {
+		runTags[k] = v
+	}
+	return runTags
+}
+
+// validateRunTags checks that the run_tags do not override the tags set by
+// the builder.
+func validateRunTags(tags map[string]string) []error {
+	var errs []error
+	for k, v := range tags {
+		k = strings.TrimSpace(k)
+		switch {
+		case k == "":
+			errs = append(errs, fmt.Errorf("run_tags: tag key empty"))
+		case k == runTagBuildName || k == runTagBuildUUID:
+			errs = append(errs, fmt.Errorf("run_tags: %s is set by the builder", k))
+		case strings.TrimSpace(v) == "":
+			errs = append(errs, fmt.Errorf("run_tags: tag %s has an empty value", k))
+		}
+	}
+	return errs
+}
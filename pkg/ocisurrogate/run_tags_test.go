@@ -0,0 +1,40 @@
+package ocisurrogate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunTags(t *testing.T) {
+	tags := runTags(map[string]string{"team": "platform"}, "ol8", "a1b2")
+	expected := map[string]string{
+		"team":              "platform",
+		"packer_build_name": "ol8",
+		"packer_build_uuid": "a1b2",
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("bad run tags: %v", tags)
+	}
+
+	tags = runTags(nil, "", "a1b2")
+	if expected := map[string]string{"packer_build_uuid": "a1b2"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("bad run tags without a build name: %v", tags)
+	}
+}
+
+func TestValidateRunTags(t *testing.T) {
+	if errs := validateRunTags(map[string]string{"team": "platform"}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	errs := validateRunTags(map[string]string{"packer_build_uuid": "mine"})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "set by the builder") {
+		t.Errorf("expected an error overriding a builder tag, got %v", errs)
+	}
+
+	errs = validateRunTags(map[string]string{"team": " "})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "empty value") {
+		t.Errorf("expected an empty value error, got %v", errs)
+	}
+}
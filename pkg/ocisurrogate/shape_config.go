@@ -0,0 +1,59 @@
+//go:generate mapstructure-to-hcl2 -type ShapeConfig
+
+package ocisurrogate
+
+import (
+	"fmt"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// ShapeConfig sizes an instance launched on a flexible shape, such as
+// VM.Standard.E3.Flex.
+type ShapeConfig struct {
+	// Ocpus is the number of OCPUs of the instance.
+	Ocpus *float32 `mapstructure:"ocpus"`
+	// MemoryInGBs is the amount of memory of the instance.
+	MemoryInGBs *float32 `mapstructure:"memory_in_gbs"`
+	// BaselineOcpuUtilization makes the instance burstable: BASELINE_1_8,
+	// BASELINE_1_2 or BASELINE_1_1 (the default, not burstable).
+	BaselineOcpuUtilization string `mapstructure:"baseline_ocpu_utilization"`
+}
+
+func (s *ShapeConfig) Prepare(name string) []error {
+	var errs []error
+
+	if s.Ocpus != nil && *s.Ocpus <= 0 {
+		errs = append(errs, fmt.Errorf("%s: ocpus must be positive, got %v", name, *s.Ocpus))
+	}
+	if s.MemoryInGBs != nil && *s.MemoryInGBs <= 0 {
+		errs = append(errs, fmt.Errorf("%s: memory_in_gbs must be positive, got %v", name, *s.MemoryInGBs))
+	}
+	switch s.BaselineOcpuUtilization {
+	case "", "BASELINE_1_8", "BASELINE_1_2", "BASELINE_1_1":
+	default:
+		errs = append(errs, fmt.Errorf(
+			"%s: baseline_ocpu_utilization must be one of BASELINE_1_8, BASELINE_1_2 or BASELINE_1_1, got %q",
+			name, s.BaselineOcpuUtilization))
+	}
+
+	return errs
+}
+
+// isSet reports whether any setting is given.
+func (s *ShapeConfig) isSet() bool {
+	return s.Ocpus != nil || s.MemoryInGBs != nil || s.BaselineOcpuUtilization != ""
+}
+
+// shapeConfig returns the shape configuration of a launch request, nil
+// when none is set.
+func (s *ShapeConfig) shapeConfig() *core.LaunchInstanceShapeConfigDetails {
+	if !s.isSet() {
+		return nil
+	}
+	return &core.LaunchInstanceShapeConfigDetails{
+		Ocpus:                   s.Ocpus,
+		MemoryInGBs:             s.MemoryInGBs,
+		BaselineOcpuUtilization: core.LaunchInstanceShapeConfigDetailsBaselineOcpuUtilizationEnum(s.BaselineOcpuUtilization),
+	}
+}
@@ -0,0 +1,43 @@
+package ocisurrogate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestShapeConfigPrepare(t *testing.T) {
+	ocpus, memory := float32(2), float32(16)
+	s := ShapeConfig{Ocpus: &ocpus, MemoryInGBs: &memory, BaselineOcpuUtilization: "BASELINE_1_2"}
+	if errs := s.Prepare("shape_config"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	expected := &core.LaunchInstanceShapeConfigDetails{
+		Ocpus:                   &ocpus,
+		MemoryInGBs:             &memory,
+		BaselineOcpuUtilization: core.LaunchInstanceShapeConfigDetailsBaselineOcpuUtilizationEnum("BASELINE_1_2"),
+	}
+	if config := s.shapeConfig(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("bad shape config: %#v", config)
+	}
+}
+
+func TestShapeConfigPrepare_Invalid(t *testing.T) {
+	ocpus, memory := float32(0), float32(-1)
+	s := ShapeConfig{Ocpus: &ocpus, MemoryInGBs: &memory, BaselineOcpuUtilization: "BASELINE_1_4"}
+	if errs := s.Prepare("shape_config"); len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+}
+
+func TestShapeConfigPrepare_Unset(t *testing.T) {
+	var s ShapeConfig
+	if errs := s.Prepare("shape_config"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if config := s.shapeConfig(); config != nil {
+		t.Errorf("should not set a shape config, got %#v", config)
+	}
+}
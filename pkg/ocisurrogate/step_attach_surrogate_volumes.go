@@ -0,0 +1,77 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepAttachSurrogateVolumes records the attachments of the surrogate_volumes
+// that were attached to the surrogate instance at launch and waits for them.
+type stepAttachSurrogateVolumes struct {
+	volumes []*createdVolume
+}
+
+func (s *stepAttachSurrogateVolumes) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		id     = state.Get("instance_surrogate_id").(string)
+	)
+
+	volumesRaw, ok := state.GetOk("surrogate_volumes")
+	if !ok {
+		return multistep.ActionContinue
+	}
+	s.volumes = volumesRaw.([]*createdVolume)
+
+	attachments, err := driver.ListInstanceVolumeAttachments(ctx, id)
+	if err != nil {
+		err = fmt.Errorf("Problem listing the volume attachments of the surrogate instance: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	for _, volume := range s.volumes {
+		for _, attachment := range attachments {
+			if *attachment.GetVolumeId() == volume.ID {
+				volume.AttachmentID = *attachment.GetId()
+			}
+		}
+		if volume.AttachmentID == "" {
+			err := fmt.Errorf("Surrogate volume %s is not attached to the surrogate instance", volume.ID)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+
+		ui.Say(fmt.Sprintf("Waiting for volume %s to be attached to the surrogate instance...", volume.ID))
+		err := driver.WaitForVolumeAttachmentState(ctx, volume.AttachmentID, []string{"ATTACHING"}, "ATTACHED")
+		if err != nil {
+			err = fmt.Errorf("Error waiting for surrogate volume to be attached: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+// Cleanup detaches the volumes from the surrogate instance before it is
+// terminated, so that stepCreateSurrogateVolumes finds them detached.
+func (s *stepAttachSurrogateVolumes) Cleanup(state multistep.StateBag) {
+	driver := state.Get("driver").(Driver)
+	ui := state.Get("ui").(packer.Ui)
+
+	for _, volume := range s.volumes {
+		if err := detachVolume(context.TODO(), driver, ui, volume); err != nil {
+			err = fmt.Errorf("Error detaching surrogate volume. Please delete it manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/builder"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// iscsiTarget is the iSCSI target of a volume attached with the iscsi
+// attachment_type.
+type iscsiTarget struct {
+	IQN  string
+	IPv4 string
+	Port int
+}
+
+// stepAttachmentInfo resolves the device path of the surrogate volume on the
+// builder instance and, for iSCSI attachments, its target, and publishes them
+// to the provisioners.
+//
+// Produces:
+//
+//	surrogate_device        string       - Device path of the surrogate volume
+//	surrogate_iscsi_target  *iscsiTarget - iSCSI target, for iscsi attachments
+type stepAttachmentInfo struct{}
+
+func (s *stepAttachmentInfo) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver       = state.Get("driver").(Driver)
+		ui           = state.Get("ui").(packer.Ui)
+		config       = state.Get("config").(*Config)
+		attachmentID = state.Get("attached_volume_id").(string)
+	)
+
+	attachment, err := driver.GetVolumeAttachment(ctx, attachmentID)
+	if err != nil {
+		err = fmt.Errorf("Error getting surrogate volume attachment: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	device := config.SurrogateDevice
+	if device == "" && attachment.GetDevice() != nil {
+		device = *attachment.GetDevice()
+	}
+	if device == "" {
+		device = "/dev/sdb"
+	}
+	state.Put("surrogate_device", device)
+	ui.Say(fmt.Sprintf("Surrogate volume attached as %s at %s.", config.AttachmentType, device))
+
+	target := &iscsiTarget{}
+	if iscsi, ok := attachment.(core.IScsiVolumeAttachment); ok {
+		target.IQN = *iscsi.Iqn
+		target.IPv4 = *iscsi.Ipv4
+		target.Port = *iscsi.Port
+		state.Put("surrogate_iscsi_target", target)
+		ui.Message(fmt.Sprintf("iSCSI target: %s at %s:%d", target.IQN, target.IPv4, target.Port))
+	}
+
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("SurrogateDevice", device)
+	generatedData.Put("SurrogateIscsiIqn", target.IQN)
+	generatedData.Put("SurrogateIscsiIpv4", target.IPv4)
+	generatedData.Put("SurrogateIscsiPort", target.Port)
+
+	return multistep.ActionContinue
+}
+
+// surrogateDevice returns the device the surrogate volume is partitioned
+// and mounted from: the surrogate_layout device when set, otherwise the
+// device resolved when the volume was attached.
+func surrogateDevice(state multistep.StateBag) string {
+	config := state.Get("config").(*Config)
+	if config.SurrogateLayout.Device != "" {
+		return config.SurrogateLayout.Device
+	}
+	return state.Get("surrogate_device").(string)
+}
+
+func (s *stepAttachmentInfo) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
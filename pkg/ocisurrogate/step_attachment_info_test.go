@@ -0,0 +1,67 @@
+package ocisurrogate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepAttachmentInfo(t *testing.T) {
+	state := testState()
+	state.Put("attached_volume_id", "ocid1...")
+
+	step := new(stepAttachmentInfo)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if device := state.Get("surrogate_device").(string); device != "/dev/sdb" {
+		t.Errorf("expected device /dev/sdb, got %s", device)
+	}
+	if _, ok := state.GetOk("surrogate_iscsi_target"); ok {
+		t.Errorf("should NOT have surrogate_iscsi_target")
+	}
+
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if generatedData["SurrogateDevice"] != "/dev/sdb" {
+		t.Errorf("bad SurrogateDevice: %v", generatedData["SurrogateDevice"])
+	}
+}
+
+func TestStepAttachmentInfo_ISCSI(t *testing.T) {
+	state := testState()
+	state.Put("attached_volume_id", "ocid1...")
+
+	iqn, ipv4, port, device := "iqn.2015-12.com.oracleiaas:abcd", "169.254.2.2", 3260, "/dev/oracleoci/oraclevdb"
+	driver := state.Get("driver").(*driverMock)
+	driver.GetVolumeAttachmentResult = core.IScsiVolumeAttachment{
+		Iqn:    &iqn,
+		Ipv4:   &ipv4,
+		Port:   &port,
+		Device: &device,
+	}
+
+	step := new(stepAttachmentInfo)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if d := state.Get("surrogate_device").(string); d != device {
+		t.Errorf("expected device %s, got %s", device, d)
+	}
+	target := state.Get("surrogate_iscsi_target").(*iscsiTarget)
+	if target.IQN != iqn || target.IPv4 != ipv4 || target.Port != port {
+		t.Errorf("bad iSCSI target: %+v", target)
+	}
+
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if generatedData["SurrogateIscsiIqn"] != iqn || generatedData["SurrogateIscsiPort"] != port {
+		t.Errorf("bad generated data: %v", generatedData)
+	}
+}
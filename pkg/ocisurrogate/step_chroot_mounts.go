@@ -0,0 +1,93 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepChrootMounts mounts chroot_mounts inside the mounted surrogate so that
+// it can be used as a chroot.
+//
+// Produces:
+//
+//	chroot_mounts_cleanup Cleanup - To perform early cleanup
+type stepChrootMounts struct {
+	mounts []string
+}
+
+func (s *stepChrootMounts) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui        = state.Get("ui").(packer.Ui)
+		config    = state.Get("config").(*Config)
+		mountPath = state.Get("mount_path").(string)
+	)
+
+	if !config.UseChroot {
+		return multistep.ActionContinue
+	}
+
+	if err := checkSurrogateMounted(ctx, state, mountPath); err != nil {
+		err = fmt.Errorf("Error preparing the chroot: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say("Mounting additional paths within the chroot...")
+
+	s.mounts = make([]string, 0, len(config.ChrootMounts))
+	state.Put("chroot_mounts_cleanup", s)
+
+	for _, mountInfo := range config.ChrootMounts {
+		target := path.Join(mountPath, mountInfo[2])
+
+		flags := "-t " + shellQuote(mountInfo[0])
+		if mountInfo[0] == "bind" {
+			flags = "--bind"
+		}
+
+		ui.Message(fmt.Sprintf("Mounting: %s", mountInfo[2]))
+		command := fmt.Sprintf("mkdir -p %s && mount %s %s %s",
+			shellQuote(target), flags, shellQuote(mountInfo[1]), shellQuote(target))
+		if _, err := runRemoteCommand(ctx, state, command); err != nil {
+			err = fmt.Errorf("Error mounting %s in the chroot: %s", mountInfo[2], err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+
+		s.mounts = append(s.mounts, target)
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepChrootMounts) Cleanup(state multistep.StateBag) {
+	ui := state.Get("ui").(packer.Ui)
+
+	if err := s.CleanupFunc(state); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// CleanupFunc unmounts the chroot mounts in reverse order, lazily if they
+// are still busy. It is safe to call more than once.
+func (s *stepChrootMounts) CleanupFunc(state multistep.StateBag) error {
+	for len(s.mounts) > 0 {
+		var target string
+		lastIndex := len(s.mounts) - 1
+		target, s.mounts = s.mounts[lastIndex], s.mounts[:lastIndex]
+
+		quoted := shellQuote(target)
+		command := fmt.Sprintf("! mountpoint -q %s || umount %s || umount -l %s", quoted, quoted, quoted)
+		if _, err := runRemoteCommand(context.TODO(), state, command); err != nil {
+			return fmt.Errorf("Error unmounting %s: %s", target, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package ocisurrogate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepChrootMounts(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+	config := state.Get("config").(*Config)
+	config.UseChroot = true
+
+	step := new(stepChrootMounts)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	if len(comm.Commands) != len(config.ChrootMounts)+1 {
+		t.Fatalf("expected a mountpoint check and %d mount commands, got %v", len(config.ChrootMounts), comm.Commands)
+	}
+	if expected := "mountpoint -q '/mnt'"; comm.Commands[0] != expected {
+		t.Errorf("bad mountpoint check:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+	if expected := "mkdir -p '/mnt/dev' && mount --bind '/dev' '/mnt/dev'"; comm.Commands[1] != expected {
+		t.Errorf("bad mount command:\n got: %s\nwant: %s", comm.Commands[1], expected)
+	}
+
+	comm.Commands = nil
+	step.Cleanup(state)
+	if expected := "! mountpoint -q '/mnt/sys' || umount '/mnt/sys' || umount -l '/mnt/sys'"; comm.Commands[0] != expected {
+		t.Errorf("bad unmount command:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+}
+
+func TestStepChrootMounts_NotMounted(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+	config := state.Get("config").(*Config)
+	config.UseChroot = true
+	comm := state.Get("communicator").(*commandRecorder)
+	comm.StartExitStatus = 1
+
+	step := new(stepChrootMounts)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+	if len(comm.Commands) != 1 {
+		t.Fatalf("should only check the mount path, got %v", comm.Commands)
+	}
+}
+
+func TestStepChrootMounts_Disabled(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+
+	step := new(stepChrootMounts)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("chroot_mounts_cleanup"); ok {
+		t.Fatalf("should NOT have chroot_mounts_cleanup")
+	}
+}
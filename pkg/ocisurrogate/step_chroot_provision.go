@@ -0,0 +1,28 @@
+package ocisurrogate
+
+import (
+	"context"
+
+	"github.com/hashicorp/packer/common"
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+// stepChrootProvision runs the provisioners inside the mounted surrogate,
+// through a communicator that wraps every command in a chroot.
+type stepChrootProvision struct {
+	provision *common.StepProvision
+}
+
+func (s *stepChrootProvision) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	s.provision = &common.StepProvision{
+		Comm: newChrootCommunicator(state),
+	}
+
+	return s.provision.Run(ctx, state)
+}
+
+func (s *stepChrootProvision) Cleanup(state multistep.StateBag) {
+	if s.provision != nil {
+		s.provision.Cleanup(state)
+	}
+}
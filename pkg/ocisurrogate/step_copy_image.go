@@ -0,0 +1,147 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// copyURLLifetime is how long the pre-authenticated request the copies are
+// imported from is valid for. It is deleted as soon as they are available.
+const copyURLLifetime = 24 * time.Hour
+
+// stepCopyImage imports the exported image in each of copy_to_regions and
+// image_copy_targets, and waits for the copies to be available
+// concurrently. The copies are deleted if the build halts or is cancelled.
+//
+// Produces:
+//
+//	image_copies []ImageCopy - Copies of the image
+type stepCopyImage struct {
+	parID  string
+	copies []ImageCopy
+}
+
+func (s *stepCopyImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		region = state.Get("region").(string)
+		export = &config.Export
+	)
+
+	targets := copyTargets(config, region)
+	if len(targets) == 0 {
+		return multistep.ActionContinue
+	}
+
+	objectName := state.Get("export_object_name").(string)
+
+	ui.Say(fmt.Sprintf("Copying image to %d regions or compartments...", len(targets)))
+
+	accessURI, parID, err := driver.CreatePreauthenticatedRequest(ctx, export, objectName, objectName+"-copy", time.Now().Add(copyURLLifetime))
+	if err != nil {
+		return s.halt(state, fmt.Errorf("Error creating pre-authenticated request for %s: %s", objectName, err))
+	}
+	s.parID = parID
+	sourceURI := preauthenticatedURL(region, accessURI)
+
+	s.copies = make([]ImageCopy, 0, len(targets))
+	for _, target := range targets {
+		image, err := driver.ImportImage(ctx, target.Region, target.CompartmentID, sourceURI, export.Format)
+		if err != nil {
+			return s.halt(state, fmt.Errorf("Error importing image in %s: %s", target.Region, err))
+		}
+		target.ID = *image.Id
+		s.copies = append(s.copies, target)
+		ui.Message(fmt.Sprintf("Importing image in %s as %s", target.Region, target.ID))
+	}
+
+	ui.Say("Waiting for the image copies to become available...")
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs *packer.MultiError
+	)
+	for _, c := range s.copies {
+		wg.Add(1)
+		go func(c ImageCopy) {
+			defer wg.Done()
+			if err := driver.WaitForRegionalImageCreation(ctx, c.Region, c.ID); err != nil {
+				mu.Lock()
+				errs = packer.MultiErrorAppend(errs, fmt.Errorf("%s: %s", c.ID, err))
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+	if errs != nil {
+		return s.halt(state, fmt.Errorf("Error waiting for the image copies: %s", errs))
+	}
+
+	state.Put("image_copies", s.copies)
+
+	ui.Say("Image copied.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCopyImage) Cleanup(state multistep.StateBag) {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	if s.parID != "" {
+		if err := driver.DeletePreauthenticatedRequest(context.TODO(), &config.Export, s.parID); err != nil {
+			ui.Error(fmt.Sprintf("Error deleting pre-authenticated request %s: %s", s.parID, err))
+		}
+		s.parID = ""
+	}
+
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	_, halted := state.GetOk(multistep.StateHalted)
+	if !cancelled && !halted {
+		return
+	}
+
+	for _, c := range s.copies {
+		ui.Say(fmt.Sprintf("Deleting image copy %s in %s...", c.ID, c.Region))
+		if err := driver.DeleteRegionalImage(context.TODO(), c.Region, c.ID); err != nil {
+			ui.Error(fmt.Sprintf("Error deleting image copy %s in %s, you may need to delete it manually: %s", c.ID, c.Region, err))
+		}
+	}
+}
+
+func (s *stepCopyImage) halt(state multistep.StateBag, err error) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Error(err.Error())
+	state.Put("error", err)
+	return multistep.ActionHalt
+}
+
+// copyTargets returns the copies of the image to create, those of
+// copy_to_regions first. image_copy_targets default to the region of the
+// build.
+func copyTargets(config *Config, region string) []ImageCopy {
+	var targets []ImageCopy
+	for _, c := range config.CopyToRegions {
+		targets = append(targets, ImageCopy{Region: c.Region, CompartmentID: c.CompartmentID})
+	}
+	for _, t := range config.ImageCopyTargets {
+		target := ImageCopy{Region: t.Region, CompartmentID: t.CompartmentID}
+		if target.Region == "" {
+			target.Region = region
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
@@ -0,0 +1,109 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func testCopyImageState() multistep.StateBag {
+	state := testState()
+	state.Put("region", "us-ashburn-1")
+	state.Put("export_object_name", "HelloWorld.oci")
+	config := state.Get("config").(*Config)
+	config.Export = ImageExport{Bucket: "images", Namespace: "ns", Format: "OCI"}
+	config.CopyToRegions = []RegionCopy{
+		{Region: "us-phoenix-1", CompartmentID: "ocid1.compartment.phx"},
+	}
+	config.ImageCopyTargets = []ImageCopyTarget{
+		{CompartmentID: "ocid1.compartment.partner"},
+	}
+	return state
+}
+
+func TestStepCopyImage(t *testing.T) {
+	state := testCopyImageState()
+
+	step := new(stepCopyImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+	if len(driver.PreauthenticatedRequestNames) != 1 || driver.PreauthenticatedRequestNames[0] != "HelloWorld.oci-copy" {
+		t.Fatalf("bad pre-authenticated requests: %v", driver.PreauthenticatedRequestNames)
+	}
+	expected := "https://objectstorage.us-ashburn-1.oraclecloud.com/p/secret/n/ns/b/images/o/HelloWorld.oci"
+	if driver.ImportImageSourceURI != expected {
+		t.Fatalf("bad import source: %s", driver.ImportImageSourceURI)
+	}
+	if driver.ImportImageCompartmentIDs["us-phoenix-1"] != "ocid1.compartment.phx" {
+		t.Fatalf("bad import compartments: %v", driver.ImportImageCompartmentIDs)
+	}
+
+	copies := state.Get("image_copies").([]ImageCopy)
+	expectedCopies := []ImageCopy{
+		{Region: "us-phoenix-1", CompartmentID: "ocid1.compartment.phx", ID: "ocid1.image.oc1.us-phoenix-1"},
+		{Region: "us-ashburn-1", CompartmentID: "ocid1.compartment.partner", ID: "ocid1.image.oc1.us-ashburn-1"},
+	}
+	if len(copies) != len(expectedCopies) || copies[0] != expectedCopies[0] || copies[1] != expectedCopies[1] {
+		t.Fatalf("bad image_copies:\n got: %v\nwant: %v", copies, expectedCopies)
+	}
+
+	step.Cleanup(state)
+	if driver.DeletePreauthenticatedRequestID != "ocid1.par.HelloWorld.oci-copy" {
+		t.Fatalf("should delete the pre-authenticated request")
+	}
+	if len(driver.DeleteRegionalImageIDs) != 0 {
+		t.Fatalf("should keep the copies, deleted %v", driver.DeleteRegionalImageIDs)
+	}
+}
+
+func TestStepCopyImage_WaitErr(t *testing.T) {
+	state := testCopyImageState()
+
+	step := new(stepCopyImage)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.WaitForRegionalImageCreationErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+	if _, ok := state.GetOk("image_copies"); ok {
+		t.Fatalf("should NOT have image_copies")
+	}
+
+	state.Put(multistep.StateHalted, true)
+	step.Cleanup(state)
+	if len(driver.DeleteRegionalImageIDs) != 2 {
+		t.Fatalf("should delete both copies, got %v", driver.DeleteRegionalImageIDs)
+	}
+	if driver.DeletePreauthenticatedRequestID != "ocid1.par.HelloWorld.oci-copy" {
+		t.Fatalf("should delete the pre-authenticated request")
+	}
+}
+
+func TestStepCopyImage_Unset(t *testing.T) {
+	state := testState()
+	state.Put("region", "us-ashburn-1")
+
+	step := new(stepCopyImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+	if driver.ImportImageCompartmentIDs != nil {
+		t.Fatalf("should not import any copy")
+	}
+}
@@ -0,0 +1,126 @@
+package ocisurrogate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepCopyRoot copies the root filesystem of the builder instance into the
+// mounted surrogate, followed by the separately mounted copy_root_paths.
+type stepCopyRoot struct{}
+
+func (s *stepCopyRoot) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui        = state.Get("ui").(packer.Ui)
+		config    = state.Get("config").(*Config)
+		mountPath = state.Get("mount_path").(string)
+	)
+
+	if !config.CopyRoot {
+		return multistep.ActionContinue
+	}
+
+	if err := checkSurrogateMounted(ctx, state, mountPath); err != nil {
+		err = fmt.Errorf("Error copying root filesystem: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say(fmt.Sprintf("Copying root filesystem of the builder instance to %s...", mountPath))
+
+	commands := []string{rsyncCommand("/", mountPath, config.CopyRootExcludes, false)}
+	for _, p := range config.CopyRootPaths {
+		target := path.Join(mountPath, p)
+		commands = append(commands, fmt.Sprintf("! mountpoint -q %s || { mkdir -p %s && %s; }",
+			shellQuote(p), shellQuote(target), rsyncCommand(p, target, config.CopyRootExcludes, true)))
+	}
+	if config.SELinuxRelabel {
+		commands = append(commands, fmt.Sprintf("touch %s", shellQuote(path.Join(mountPath, ".autorelabel"))))
+	}
+
+	for _, command := range commands {
+		progress := &progressWriter{ui: ui, interval: 15 * time.Second}
+		if err := streamRemoteCommand(ctx, state, command, progress); err != nil {
+			err = fmt.Errorf("Error copying root filesystem: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+		progress.Flush()
+	}
+
+	ui.Say("Root filesystem copied.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCopyRoot) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
+
+// rsyncCommand returns the command copying the filesystem mounted on src to
+// dst, preserving hard links, ACLs, extended attributes and SELinux labels,
+// without crossing into other filesystems.
+func rsyncCommand(src string, dst string, excludes []string, delete bool) string {
+	args := []string{"rsync -aHAXx --numeric-ids --info=progress2"}
+	if delete {
+		args = append(args, "--delete")
+	}
+	for _, exclude := range excludes {
+		args = append(args, shellQuote("--exclude="+exclude))
+	}
+	args = append(args, shellQuote(strings.TrimSuffix(src, "/")+"/"), shellQuote(strings.TrimSuffix(dst, "/")+"/"))
+	return strings.Join(args, " ")
+}
+
+// progressWriter reports the progress lines written by rsync --info=progress2,
+// which are separated by carriage returns, to the Ui at most once per
+// interval.
+type progressWriter struct {
+	ui       packer.Ui
+	interval time.Duration
+
+	buf  bytes.Buffer
+	last string
+	sent time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		i := bytes.IndexAny(data, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(data[:i]))
+		w.buf.Next(i + 1)
+		if line == "" {
+			continue
+		}
+		w.last = line
+		if time.Since(w.sent) >= w.interval {
+			w.ui.Message(line)
+			w.sent = time.Now()
+			w.last = ""
+		}
+	}
+	return len(p), nil
+}
+
+// Flush reports the last progress line if it was held back.
+func (w *progressWriter) Flush() {
+	if line := strings.TrimSpace(w.last + w.buf.String()); line != "" {
+		w.ui.Message(line)
+	}
+	w.buf.Reset()
+	w.last = ""
+}
@@ -0,0 +1,96 @@
+package ocisurrogate
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+func TestStepCopyRoot(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+	config := state.Get("config").(*Config)
+	config.CopyRoot = true
+	config.CopyRootExcludes = []string{"/var/cache/*"}
+	config.SELinuxRelabel = true
+
+	step := new(stepCopyRoot)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	expected := []string{
+		"mountpoint -q '/mnt'",
+		"rsync -aHAXx --numeric-ids --info=progress2 '--exclude=/var/cache/*' '/' '/mnt/'",
+		"! mountpoint -q '/boot' || { mkdir -p '/mnt/boot' && rsync -aHAXx --numeric-ids --info=progress2 --delete '--exclude=/var/cache/*' '/boot/' '/mnt/boot/'; }",
+		"! mountpoint -q '/boot/efi' || { mkdir -p '/mnt/boot/efi' && rsync -aHAXx --numeric-ids --info=progress2 --delete '--exclude=/var/cache/*' '/boot/efi/' '/mnt/boot/efi/'; }",
+		"touch '/mnt/.autorelabel'",
+	}
+	if strings.Join(comm.Commands, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("bad commands:\n got: %v\nwant: %v", comm.Commands, expected)
+	}
+}
+
+func TestStepCopyRoot_NotMounted(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+	config := state.Get("config").(*Config)
+	config.CopyRoot = true
+	comm := state.Get("communicator").(*commandRecorder)
+	comm.StartExitStatus = 1
+
+	step := new(stepCopyRoot)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+	if len(comm.Commands) != 1 {
+		t.Fatalf("should not copy anything, got %v", comm.Commands)
+	}
+}
+
+func TestStepCopyRoot_Disabled(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+
+	step := new(stepCopyRoot)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if comm := state.Get("communicator").(*commandRecorder); len(comm.Commands) != 0 {
+		t.Fatalf("should not run any command, got %v", comm.Commands)
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	ui := &packer.BasicUi{Reader: new(bytes.Buffer), Writer: out}
+
+	w := &progressWriter{ui: ui, interval: time.Hour}
+	w.Write([]byte("  1,024   1%  \r  2,048   2%"))
+	w.Write([]byte("  \r  4,096 100%\n"))
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected the first and last progress lines, got %q", out.String())
+	}
+	if !strings.Contains(lines[0], "1,024") || !strings.Contains(lines[1], "4,096 100%") {
+		t.Fatalf("bad progress lines: %q", lines)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/packer/builder"
 	"github.com/hashicorp/packer/helper/multistep"
 	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
 type stepCreateInstance struct{}
@@ -19,7 +21,8 @@ func (s *stepCreateInstance) Run(ctx context.Context, state multistep.StateBag)
 
 	ui.Say("Creating instance...")
 
-	instanceID, err := driver.CreateInstance(ctx, string(config.Comm.SSHPublicKey),"")
+	sourceImage := state.Get("source_image").(core.Image)
+	instanceID, err := driver.CreateInstance(ctx, string(config.Comm.SSHPublicKey), *sourceImage.Id, "", nil)
 	if err != nil {
 		err = fmt.Errorf("Problem creating instance: %s", err)
 		ui.Error(err.Error())
@@ -29,6 +32,9 @@ func (s *stepCreateInstance) Run(ctx context.Context, state multistep.StateBag)
 
 	state.Put("instance_id", instanceID)
 
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("InstanceOCID", instanceID)
+
 	ui.Say(fmt.Sprintf("Created instance (%s).", instanceID))
 
 	ui.Say("Waiting for instance to enter 'RUNNING' state...")
@@ -41,6 +47,20 @@ func (s *stepCreateInstance) Run(ctx context.Context, state multistep.StateBag)
 	}
 
 	ui.Say("Instance 'RUNNING'.")
+
+	// With surrogate_source image, the seed volume is used instead of a
+	// clone and deleted with it.
+	if seedVolumeID, ok := state.GetOk("surrogate_source_volume_id"); ok {
+		state.Put("cloned_volume_id", seedVolumeID.(string))
+		return s.attach(ctx, state, instanceID, seedVolumeID.(string))
+	}
+
+	// With surrogate_source empty, a blank volume is provisioned from
+	// scratch instead.
+	if config.SurrogateSource == "empty" {
+		return s.createEmpty(ctx, state, instanceID)
+	}
+
 	ui.Say("Cloning Boot Volume to surrogate ...")
 
 	clonedVolumeID, err := driver.CreateBootClone(ctx, instanceID)
@@ -61,6 +81,51 @@ func (s *stepCreateInstance) Run(ctx context.Context, state multistep.StateBag)
 	}
 	ui.Say("Surrogate Boot Volume in 'AVAILABLE' State.")
 
+	return s.attach(ctx, state, instanceID, clonedVolumeID)
+}
+
+// createEmpty creates a blank block volume of surrogate_size_in_gbs and
+// attaches it to the builder instance as the surrogate volume.
+func (s *stepCreateInstance) createEmpty(ctx context.Context, state multistep.StateBag, instanceID string) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	ui.Say(fmt.Sprintf("Creating empty surrogate volume of %d GB...", config.SurrogateSizeInGBs))
+
+	volumeID, err := driver.CreateVolume(ctx, &SurrogateVolume{
+		Name:      config.ImageName + "-surrogate",
+		SizeInGBs: config.SurrogateSizeInGBs,
+	})
+	if err != nil {
+		err = fmt.Errorf("Problem creating empty surrogate volume: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	state.Put("cloned_volume_id", volumeID)
+
+	ui.Say("Waiting for empty surrogate volume to enter 'AVAILABLE' state...")
+	if err = driver.WaitForVolumeState(ctx, volumeID, []string{"PROVISIONING"}, "AVAILABLE"); err != nil {
+		err = fmt.Errorf("Error waiting for Volume to be available: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	ui.Say("Empty surrogate volume in 'AVAILABLE' State.")
+
+	return s.attach(ctx, state, instanceID, volumeID)
+}
+
+// attach attaches the surrogate volume to the builder instance.
+func (s *stepCreateInstance) attach(ctx context.Context, state multistep.StateBag, instanceID string, clonedVolumeID string) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+	)
+
 	ui.Say(fmt.Sprintf("Attaching Cloned Volume to instance (%s).", clonedVolumeID))
 	attachedVolumeID, err := driver.AttachBootClone(ctx, instanceID, clonedVolumeID)
 	if err != nil {
@@ -80,12 +145,17 @@ func (s *stepCreateInstance) Run(ctx context.Context, state multistep.StateBag)
 	ui.Say(fmt.Sprintf("Cloned Volume Attached successfully to instance (%s) with id %s.", instanceID, attachedVolumeID))
 	state.Put("attached_volume_id", attachedVolumeID)
 
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("SurrogateVolumeOCID", clonedVolumeID)
+	generatedData.Put("SurrogateAttachmentOCID", attachedVolumeID)
+
 	return multistep.ActionContinue
 }
 
 func (s *stepCreateInstance) Cleanup(state multistep.StateBag) {
 	driver := state.Get("driver").(Driver)
 	ui := state.Get("ui").(packer.Ui)
+	config := state.Get("config").(*Config)
 
 	idRaw, ok := state.GetOk("instance_id")
 	if !ok {
@@ -93,6 +163,8 @@ func (s *stepCreateInstance) Cleanup(state multistep.StateBag) {
 	}
 	id := idRaw.(string)
 
+	saveConsoleHistory(state, id, "builder")
+
 	ui.Say(fmt.Sprintf("Terminating instance (%s)...", id))
 
 	if err := driver.TerminateInstance(context.TODO(), id); err != nil {
@@ -119,6 +191,25 @@ func (s *stepCreateInstance) Cleanup(state multistep.StateBag) {
 		idVolume := idVolumeRaw.(string)
 
 		ui.Say(fmt.Sprintf("Deleting Surrogate Volume (%s)...", idVolume))
+		if config.SurrogateSource == "empty" {
+			if err := driver.DeleteVolume(context.TODO(), idVolume); err != nil {
+				err = fmt.Errorf("Error terminating Surrogate Volume. Please terminate manually: %s", err)
+				ui.Error(err.Error())
+				state.Put("error", err)
+				return
+			}
+
+			err = driver.WaitForVolumeState(context.TODO(), idVolume, []string{"TERMINATING"}, "TERMINATED")
+			if err != nil {
+				err = fmt.Errorf("Error terminating Surrogate Volume. Please terminate manually: %s", err)
+				ui.Error(err.Error())
+				state.Put("error", err)
+				return
+			}
+
+			ui.Say("Deleted Surrogate Volume.")
+			return
+		}
 		if err := driver.DeleteBootVolume(context.TODO(), idVolume); err != nil {
 			err = fmt.Errorf("Error terminating Surrogate Boot Volume. Please terminate manually: %s", err)
 			ui.Error(err.Error())
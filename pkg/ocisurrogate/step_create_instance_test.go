@@ -26,6 +26,13 @@ func TestStepCreateInstance(t *testing.T) {
 		t.Fatalf("should have machine")
 	}
 
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	for _, key := range []string{"InstanceOCID", "SurrogateVolumeOCID", "SurrogateAttachmentOCID"} {
+		if generatedData[key] == nil {
+			t.Errorf("should have published %s", key)
+		}
+	}
+
 	step.Cleanup(state)
 
 	if driver.TerminateInstanceID != instanceIDRaw.(string) {
@@ -129,3 +136,64 @@ func TestStepCreateInstanceCleanup_WaitForInstanceStateErr(t *testing.T) {
 		t.Fatalf("should have error")
 	}
 }
+
+func TestStepCreateInstance_SeedVolume(t *testing.T) {
+	state := testState()
+	state.Put("publicKey", "key")
+	state.Put("surrogate_source_volume_id", "ocid1.bootvolume.seed")
+
+	step := new(stepCreateInstance)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if driver.CreateBootCloneID != "" {
+		t.Fatalf("should not have cloned the boot volume")
+	}
+	if id := state.Get("cloned_volume_id").(string); id != "ocid1.bootvolume.seed" {
+		t.Fatalf("bad cloned_volume_id: %q", id)
+	}
+	if _, ok := state.GetOk("attached_volume_id"); !ok {
+		t.Fatalf("should have attached_volume_id")
+	}
+}
+
+func TestStepCreateInstance_EmptyVolume(t *testing.T) {
+	state := testState()
+	state.Put("publicKey", "key")
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "empty"
+	config.SurrogateSizeInGBs = 100
+
+	step := new(stepCreateInstance)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if driver.CreateBootCloneID != "" {
+		t.Fatalf("should not have cloned the boot volume")
+	}
+	if len(driver.CreateVolumeIDs) != 1 {
+		t.Fatalf("should have created a blank volume, got %v", driver.CreateVolumeIDs)
+	}
+	if id := state.Get("cloned_volume_id").(string); id != driver.CreateVolumeIDs[0] {
+		t.Fatalf("bad cloned_volume_id: %q", id)
+	}
+
+	step.Cleanup(state)
+
+	if len(driver.DeleteVolumeIDs) != 1 || driver.DeleteVolumeIDs[0] != driver.CreateVolumeIDs[0] {
+		t.Fatalf("should have deleted the blank volume, got %v", driver.DeleteVolumeIDs)
+	}
+	if len(driver.DeletedBootVolumeIDs) != 0 {
+		t.Fatalf("should not have deleted a boot volume, got %v", driver.DeletedBootVolumeIDs)
+	}
+}
@@ -0,0 +1,150 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepCreateSeedVolume creates the surrogate volume when surrogate_source is
+// image. OCI only creates boot volumes from other boot volumes or their
+// backups, so a seed instance is launched from the target image and
+// terminated, preserving its boot volume, before it boots. The volume is
+// handed over to stepCreateInstance through the state:
+//
+//	surrogate_source_volume_id string - OCID of the seed boot volume
+type stepCreateSeedVolume struct{}
+
+func (s *stepCreateSeedVolume) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	if config.SurrogateSource != "image" {
+		return multistep.ActionContinue
+	}
+
+	imageID := config.BaseSurrogateImage
+	if imageID == "" {
+		images, err := driver.ListImages(ctx, &config.surrogateImageFilter)
+		if err != nil {
+			return s.halt(state, fmt.Errorf("Error resolving target image: %s", err))
+		}
+		image, err := config.surrogateImageFilter.selectImage(images)
+		if err != nil {
+			return s.halt(state, fmt.Errorf("Error resolving target image: %s", err))
+		}
+		imageID = *image.Id
+	}
+
+	ui.Say(fmt.Sprintf("Creating seed instance for the surrogate volume from image %s...", imageID))
+
+	instanceID, err := driver.CreateSeedInstance(ctx, imageID, config.SurrogateSizeInGBs)
+	if err != nil {
+		return s.halt(state, fmt.Errorf("Problem creating seed instance: %s", err))
+	}
+	state.Put("seed_instance_id", instanceID)
+
+	ui.Say(fmt.Sprintf("Created seed instance (%s).", instanceID))
+	ui.Say("Waiting for the boot volume of the seed instance...")
+
+	// The seed instance is terminated as soon as its boot volume exists,
+	// while it is still PROVISIONING, so that the target image never boots
+	// and writes to the volume (cloud-init, machine-id, SSH host keys).
+	// The boot volume is recorded before the instance is terminated so that
+	// it is deleted by Cleanup whatever happens next.
+	volumeID, err := driver.WaitForBootVolumeID(ctx, instanceID)
+	if err != nil {
+		return s.halt(state, fmt.Errorf("Error getting boot volume of seed instance: %s", err))
+	}
+	state.Put("seed_volume_id", volumeID)
+
+	// The boot volume outlives the instance, tag it so that it is swept if
+	// the build is killed.
+	if err = driver.TagBootVolume(ctx, volumeID); err != nil {
+		return s.halt(state, fmt.Errorf("Error tagging boot volume of seed instance: %s", err))
+	}
+
+	ui.Say(fmt.Sprintf("Terminating seed instance (%s), preserving boot volume %s...", instanceID, volumeID))
+
+	if err = driver.TerminateInstancePreservingBootVolume(ctx, instanceID); err != nil {
+		return s.halt(state, fmt.Errorf("Error terminating seed instance: %s", err))
+	}
+	if err = driver.WaitForInstanceState(ctx, instanceID, []string{"PROVISIONING", "TERMINATING"}, "TERMINATED"); err != nil {
+		return s.halt(state, fmt.Errorf("Error waiting for seed instance to terminate: %s", err))
+	}
+	state.Put("seed_instance_terminated", true)
+
+	if err = driver.WaitForBootVolumeState(ctx, volumeID, []string{"PROVISIONING", "RESTORING"}, "AVAILABLE"); err != nil {
+		return s.halt(state, fmt.Errorf("Error waiting for seed volume to be available: %s", err))
+	}
+	state.Put("surrogate_source_volume_id", volumeID)
+
+	ui.Say("Seed volume 'AVAILABLE'.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateSeedVolume) halt(state multistep.StateBag, err error) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+
+	ui.Error(err.Error())
+	state.Put("error", err)
+	return multistep.ActionHalt
+}
+
+func (s *stepCreateSeedVolume) Cleanup(state multistep.StateBag) {
+	driver := state.Get("driver").(Driver)
+	ui := state.Get("ui").(packer.Ui)
+
+	idRaw, ok := state.GetOk("seed_instance_id")
+	if !ok {
+		return
+	}
+	volumeRaw, hasVolume := state.GetOk("seed_volume_id")
+
+	if _, terminated := state.GetOk("seed_instance_terminated"); !terminated {
+		id := idRaw.(string)
+
+		ui.Say(fmt.Sprintf("Terminating seed instance (%s)...", id))
+		// Once its boot volume is known, it is preserved and deleted below,
+		// as the instance may already be terminating with it preserved.
+		terminate := driver.TerminateInstance
+		if hasVolume {
+			terminate = driver.TerminateInstancePreservingBootVolume
+		}
+		if err := terminate(context.TODO(), id); err != nil {
+			err = fmt.Errorf("Error terminating seed instance. Please terminate manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return
+		}
+		err := driver.WaitForInstanceState(context.TODO(), id, []string{"PROVISIONING", "TERMINATING"}, "TERMINATED")
+		if err != nil {
+			err = fmt.Errorf("Error terminating seed instance. Please terminate manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return
+		}
+	}
+
+	// Once stepCreateInstance has taken the volume over, it deletes it.
+	if !hasVolume {
+		return
+	}
+	if _, ok := state.GetOk("cloned_volume_id"); ok {
+		return
+	}
+	id := volumeRaw.(string)
+
+	ui.Say(fmt.Sprintf("Deleting seed volume (%s)...", id))
+	if err := driver.DeleteBootVolume(context.TODO(), id); err != nil {
+		err = fmt.Errorf("Error deleting seed volume. Please delete manually: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+	}
+}
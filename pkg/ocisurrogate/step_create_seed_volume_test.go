@@ -0,0 +1,195 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepCreateSeedVolume_Clone(t *testing.T) {
+	for _, source := range []string{"clone", "empty"} {
+		state := testState()
+		config := state.Get("config").(*Config)
+		config.SurrogateSource = source
+
+		step := new(stepCreateSeedVolume)
+
+		driver := state.Get("driver").(*driverMock)
+
+		if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+			t.Fatalf("%s: bad action: %#v", source, action)
+		}
+		step.Cleanup(state)
+
+		if driver.CreateSeedInstanceID != "" {
+			t.Fatalf("%s: should not have created a seed instance", source)
+		}
+		if _, ok := state.GetOk("surrogate_source_volume_id"); ok {
+			t.Fatalf("%s: should NOT have surrogate_source_volume_id", source)
+		}
+	}
+}
+
+func TestStepCreateSeedVolume(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "image"
+	config.BaseSurrogateImage = "ocid1.image.target"
+
+	step := new(stepCreateSeedVolume)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if driver.CreateSeedInstanceImageID != "ocid1.image.target" {
+		t.Fatalf("bad seed image: %q", driver.CreateSeedInstanceImageID)
+	}
+	for _, terminalState := range driver.WaitForInstanceStates {
+		if terminalState != "TERMINATED" {
+			t.Fatalf("should have terminated the seed instance before it boots, waited for %s", terminalState)
+		}
+	}
+	if driver.PreservedBootVolumeInstanceID != driver.CreateSeedInstanceID {
+		t.Fatalf("should have terminated the seed instance preserving its boot volume")
+	}
+	if driver.TagBootVolumeID != "ocid1.bootvolume.seed" {
+		t.Fatalf("should have tagged the seed volume, got %q", driver.TagBootVolumeID)
+	}
+	volumeID, ok := state.GetOk("surrogate_source_volume_id")
+	if !ok || volumeID.(string) != "ocid1.bootvolume.seed" {
+		t.Fatalf("bad surrogate_source_volume_id: %#v", volumeID)
+	}
+
+	step.Cleanup(state)
+
+	if driver.TerminateInstanceID != "" {
+		t.Fatalf("should not have terminated the seed instance again")
+	}
+	if driver.DeleteBootVolumeID != "ocid1.bootvolume.seed" {
+		t.Fatalf("should have deleted the seed volume, got %q", driver.DeleteBootVolumeID)
+	}
+}
+
+func TestStepCreateSeedVolume_TakenOver(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "image"
+	config.surrogateImageFilter = ImageFilter{DisplayName: "Oracle-Linux-8.2-2020.08.27-0", MostRecent: true}
+
+	step := new(stepCreateSeedVolume)
+
+	driver := state.Get("driver").(*driverMock)
+	now := time.Now()
+	driver.Images = []core.Image{
+		testImage("ocid1.image.old", "Oracle-Linux-8.2-2020.08.27-0", now.Add(-time.Hour)),
+		testImage("ocid1.image.new", "Oracle-Linux-8.2-2020.08.27-0", now),
+	}
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if driver.CreateSeedInstanceImageID != "ocid1.image.new" {
+		t.Fatalf("should have seeded from the newest target image, got %q", driver.CreateSeedInstanceImageID)
+	}
+
+	state.Put("cloned_volume_id", "ocid1.bootvolume.seed")
+	step.Cleanup(state)
+
+	if driver.DeleteBootVolumeID != "" {
+		t.Fatalf("should not have deleted the seed volume taken over by the builder")
+	}
+}
+
+func TestStepCreateSeedVolume_TagBootVolumeErr(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "image"
+	config.BaseSurrogateImage = "ocid1.image.target"
+
+	step := new(stepCreateSeedVolume)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.TagBootVolumeErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+
+	step.Cleanup(state)
+
+	if driver.TerminateInstanceID != driver.CreateSeedInstanceID {
+		t.Fatalf("should have terminated the seed instance")
+	}
+	if driver.DeleteBootVolumeID != "ocid1.bootvolume.seed" {
+		t.Fatalf("should have deleted the seed volume, got %q", driver.DeleteBootVolumeID)
+	}
+}
+
+func TestStepCreateSeedVolume_WaitForBootVolumeIDErr(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "image"
+	config.BaseSurrogateImage = "ocid1.image.target"
+
+	step := new(stepCreateSeedVolume)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.WaitForBootVolumeIDErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+
+	step.Cleanup(state)
+
+	if driver.TerminateInstanceID != driver.CreateSeedInstanceID {
+		t.Fatalf("should have terminated the seed instance")
+	}
+	if driver.DeleteBootVolumeID != "" {
+		t.Fatalf("should not have deleted a boot volume")
+	}
+}
+
+func TestStepCreateSeedVolume_WaitForTerminatedErr(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateSource = "image"
+	config.BaseSurrogateImage = "ocid1.image.target"
+
+	step := new(stepCreateSeedVolume)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.WaitForInstanceStateErr = errors.New("error")
+	driver.WaitForInstanceStateErrState = "TERMINATED"
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("surrogate_source_volume_id"); ok {
+		t.Fatalf("should NOT have surrogate_source_volume_id")
+	}
+
+	driver.WaitForInstanceStateErr = nil
+	step.Cleanup(state)
+
+	if driver.TerminateInstanceID != "" {
+		t.Fatalf("should not have terminated the seed instance without preserving its boot volume")
+	}
+	if driver.DeleteBootVolumeID != "ocid1.bootvolume.seed" {
+		t.Fatalf("should have deleted the preserved seed volume, got %q", driver.DeleteBootVolumeID)
+	}
+}
@@ -0,0 +1,76 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepCreateSurrogate(t *testing.T) {
+	state := testState()
+	state.Put("cloned_volume_id", "ocid1...")
+	state.Put("attached_volume_id", "ocid1...")
+
+	step := new(stepCreateSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("instance_surrogate_id"); !ok {
+		t.Fatalf("should have instance_surrogate_id")
+	}
+
+	step.Cleanup(state)
+	driver := state.Get("driver").(*driverMock)
+	if driver.TerminateInstanceID == "" {
+		t.Fatalf("should have terminated the surrogate instance")
+	}
+}
+
+func TestStepCreateSurrogate_LaunchVolumes(t *testing.T) {
+	state := testState()
+	state.Put("cloned_volume_id", "ocid1...")
+	state.Put("attached_volume_id", "ocid1...")
+	volumes := []*createdVolume{{ID: "ocid1.volume", Device: "/dev/oracleoci/oraclevdc"}}
+	state.Put("surrogate_volumes", volumes)
+
+	step := new(stepCreateSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+	if len(driver.CreateInstanceVolumes) != 1 || driver.CreateInstanceVolumes[0] != volumes[0] {
+		t.Fatalf("should have attached the surrogate volumes at launch: %v", driver.CreateInstanceVolumes)
+	}
+}
+
+func TestStepCreateSurrogate_DetachBootCloneErr(t *testing.T) {
+	state := testState()
+	state.Put("cloned_volume_id", "ocid1...")
+	state.Put("attached_volume_id", "ocid1...")
+
+	step := new(stepCreateSurrogate)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.DetachBootCloneErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+
+	if _, ok := state.GetOk("instance_surrogate_id"); ok {
+		t.Fatalf("should NOT have instance_surrogate_id")
+	}
+}
@@ -0,0 +1,139 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// createdVolume is a surrogate_volumes entry created for the build, along
+// with its current attachment.
+type createdVolume struct {
+	ID           string
+	AttachmentID string
+	Device       string
+}
+
+// stepCreateSurrogateVolumes creates the surrogate_volumes and attaches them
+// to the builder instance.
+//
+// Produces:
+//
+//	surrogate_volumes  []*createdVolume - The created volumes
+type stepCreateSurrogateVolumes struct {
+	volumes []*createdVolume
+}
+
+func (s *stepCreateSurrogateVolumes) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver     = state.Get("driver").(Driver)
+		ui         = state.Get("ui").(packer.Ui)
+		config     = state.Get("config").(*Config)
+		instanceID = state.Get("instance_id").(string)
+	)
+
+	if len(config.SurrogateVolumes) == 0 {
+		return multistep.ActionContinue
+	}
+
+	for i := range config.SurrogateVolumes {
+		v := &config.SurrogateVolumes[i]
+
+		ui.Say(fmt.Sprintf("Creating surrogate volume %s...", v.Name))
+		id, err := driver.CreateVolume(ctx, v)
+		if err != nil {
+			err = fmt.Errorf("Problem creating surrogate volume: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+		volume := &createdVolume{ID: id, Device: v.Device}
+		s.volumes = append(s.volumes, volume)
+		state.Put("surrogate_volumes", s.volumes)
+
+		ui.Say(fmt.Sprintf("Waiting for surrogate volume %s to enter 'AVAILABLE' state...", id))
+		if err = driver.WaitForVolumeState(ctx, id, []string{"PROVISIONING", "RESTORING"}, "AVAILABLE"); err != nil {
+			err = fmt.Errorf("Error waiting for surrogate volume to be available: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+
+		if err = attachVolume(ctx, driver, ui, instanceID, volume); err != nil {
+			err = fmt.Errorf("Problem attaching surrogate volume: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateSurrogateVolumes) Cleanup(state multistep.StateBag) {
+	driver := state.Get("driver").(Driver)
+	ui := state.Get("ui").(packer.Ui)
+
+	for i := len(s.volumes) - 1; i >= 0; i-- {
+		volume := s.volumes[i]
+
+		if err := detachVolume(context.TODO(), driver, ui, volume); err != nil {
+			err = fmt.Errorf("Error detaching surrogate volume. Please delete it manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			continue
+		}
+
+		ui.Say(fmt.Sprintf("Deleting surrogate volume (%s)...", volume.ID))
+		if err := driver.DeleteVolume(context.TODO(), volume.ID); err != nil {
+			err = fmt.Errorf("Error deleting surrogate volume. Please delete it manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			continue
+		}
+
+		err := driver.WaitForVolumeState(context.TODO(), volume.ID, []string{"TERMINATING"}, "TERMINATED")
+		if err != nil {
+			err = fmt.Errorf("Error deleting surrogate volume. Please delete it manually: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			continue
+		}
+
+		ui.Say("Deleted surrogate volume.")
+	}
+	s.volumes = nil
+}
+
+// attachVolume attaches volume to an instance and waits for the attachment.
+func attachVolume(ctx context.Context, driver Driver, ui packer.Ui, instanceID string, volume *createdVolume) error {
+	ui.Say(fmt.Sprintf("Attaching volume %s to instance (%s)...", volume.ID, instanceID))
+	attachmentID, err := driver.AttachVolume(ctx, instanceID, volume.ID, volume.Device)
+	if err != nil {
+		return err
+	}
+	volume.AttachmentID = attachmentID
+
+	return driver.WaitForVolumeAttachmentState(ctx, attachmentID, []string{"ATTACHING"}, "ATTACHED")
+}
+
+// detachVolume detaches volume from the instance it is attached to, if any,
+// and waits for the detachment.
+func detachVolume(ctx context.Context, driver Driver, ui packer.Ui, volume *createdVolume) error {
+	if volume.AttachmentID == "" {
+		return nil
+	}
+
+	ui.Say(fmt.Sprintf("Detaching volume %s...", volume.ID))
+	if err := driver.DetachVolume(ctx, volume.AttachmentID); err != nil {
+		return err
+	}
+	if err := driver.WaitForVolumeAttachmentState(ctx, volume.AttachmentID, []string{"DETACHING"}, "DETACHED"); err != nil {
+		return err
+	}
+
+	volume.AttachmentID = ""
+	return nil
+}
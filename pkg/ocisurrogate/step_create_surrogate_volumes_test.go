@@ -0,0 +1,147 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepCreateSurrogateVolumes(t *testing.T) {
+	state := testState()
+	state.Put("instance_id", "ocid1...")
+	config := state.Get("config").(*Config)
+	config.SurrogateVolumes = []SurrogateVolume{
+		{Name: "var", SizeInGBs: 50, Device: "/dev/oracleoci/oraclevdc"},
+		{Name: "home", SizeInGBs: 50, Device: "/dev/oracleoci/oraclevdd"},
+	}
+
+	step := new(stepCreateSurrogateVolumes)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	volumes := state.Get("surrogate_volumes").([]*createdVolume)
+	if len(volumes) != 2 {
+		t.Fatalf("expected 2 surrogate volumes, got %d", len(volumes))
+	}
+	for _, volume := range volumes {
+		if volume.AttachmentID == "" {
+			t.Errorf("volume %s should be attached", volume.ID)
+		}
+	}
+
+	step.Cleanup(state)
+	driver := state.Get("driver").(*driverMock)
+	if len(driver.DetachVolumeIDs) != 2 || len(driver.DeleteVolumeIDs) != 2 {
+		t.Fatalf("should have detached and deleted the volumes: %v %v",
+			driver.DetachVolumeIDs, driver.DeleteVolumeIDs)
+	}
+	if driver.DeleteVolumeIDs[0] != volumes[1].ID {
+		t.Errorf("volumes should be deleted in reverse order")
+	}
+}
+
+func TestStepCreateSurrogateVolumes_AttachVolumeErr(t *testing.T) {
+	state := testState()
+	state.Put("instance_id", "ocid1...")
+	config := state.Get("config").(*Config)
+	config.SurrogateVolumes = []SurrogateVolume{{Name: "var", SizeInGBs: 50}}
+
+	driver := state.Get("driver").(*driverMock)
+	driver.AttachVolumeErr = errors.New("error")
+
+	step := new(stepCreateSurrogateVolumes)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+
+	step.Cleanup(state)
+	if len(driver.DetachVolumeIDs) != 0 {
+		t.Errorf("should NOT detach a volume that was never attached")
+	}
+	if len(driver.DeleteVolumeIDs) != 1 {
+		t.Errorf("should have deleted the created volume")
+	}
+}
+
+func TestStepDetachSurrogateVolumes(t *testing.T) {
+	state := testState()
+	volumes := []*createdVolume{{ID: "ocid1.volume", AttachmentID: "ocid1.builderattachment"}}
+	state.Put("surrogate_volumes", volumes)
+
+	step := new(stepDetachSurrogateVolumes)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+	if len(driver.DetachVolumeIDs) != 1 || driver.DetachVolumeIDs[0] != "ocid1.builderattachment" {
+		t.Fatalf("should have detached the volume from the builder: %v", driver.DetachVolumeIDs)
+	}
+	if volumes[0].AttachmentID != "" {
+		t.Fatalf("should have cleared the builder attachment")
+	}
+}
+
+func TestStepAttachSurrogateVolumes(t *testing.T) {
+	state := testState()
+	state.Put("instance_surrogate_id", "ocid1.instance.surrogate")
+	volumes := []*createdVolume{{ID: "ocid1.volume"}}
+	state.Put("surrogate_volumes", volumes)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.VolumeAttachments = []core.VolumeAttachment{
+		core.ParavirtualizedVolumeAttachment{
+			Id:         common.String("ocid1.volumeattachment.launch"),
+			InstanceId: common.String("ocid1.instance.surrogate"),
+			VolumeId:   common.String("ocid1.volume"),
+		},
+	}
+
+	step := new(stepAttachSurrogateVolumes)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if len(driver.AttachVolumeIDs) != 0 {
+		t.Fatalf("should not attach the volumes after launch: %v", driver.AttachVolumeIDs)
+	}
+	if volumes[0].AttachmentID != "ocid1.volumeattachment.launch" {
+		t.Fatalf("should have recorded the launch attachment, got %q", volumes[0].AttachmentID)
+	}
+
+	step.Cleanup(state)
+	if volumes[0].AttachmentID != "" {
+		t.Fatalf("should have detached the volume from the surrogate")
+	}
+}
+
+func TestStepAttachSurrogateVolumes_NotAttached(t *testing.T) {
+	state := testState()
+	state.Put("instance_surrogate_id", "ocid1.instance.surrogate")
+	state.Put("surrogate_volumes", []*createdVolume{{ID: "ocid1.volume"}})
+
+	step := new(stepAttachSurrogateVolumes)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
@@ -0,0 +1,96 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepCreateZpool creates the zfs_root pool and its datasets on the
+// surrogate volume, mounted under mount_path.
+//
+// Produces:
+//
+//	zfs_root_dataset  string  - Name of the dataset mounted on /
+//	zpool_cleanup     Cleanup - To perform early cleanup
+type stepCreateZpool struct {
+	pool string
+}
+
+func (s *stepCreateZpool) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		zfs    = &config.ZFSRoot
+	)
+
+	if !zfs.enabled {
+		return multistep.ActionContinue
+	}
+
+	vdev := zfs.Vdev
+	if vdev == "" {
+		vdev = partitionDevice(surrogateDevice(state), config.SurrogateLayout.partitionNumber("zfs"))
+	}
+
+	ui.Say(fmt.Sprintf("Creating ZFS pool %s on %s...", zfs.PoolName, vdev))
+
+	command := zfs.zpoolCreateCommand(config.MountPath, vdev)
+	ui.Message(command)
+	if _, err := runRemoteCommand(ctx, state, command); err != nil {
+		err = fmt.Errorf("Error creating ZFS pool: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	s.pool = zfs.PoolName
+	state.Put("zpool_cleanup", s)
+
+	commands := append(zfs.datasetCommands(), zfs.swapCommands()...)
+	for _, command := range commands {
+		ui.Message(command)
+		if _, err := runRemoteCommand(ctx, state, command); err != nil {
+			err = fmt.Errorf("Error creating ZFS datasets: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	state.Put("zfs_root_dataset", zfs.rootDataset())
+
+	ui.Say("ZFS pool created.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateZpool) Cleanup(state multistep.StateBag) {
+	ui := state.Get("ui").(packer.Ui)
+
+	if err := s.CleanupFunc(state); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// CleanupFunc exports the pool so that it can be imported when the surrogate
+// instance boots. It is safe to call more than once.
+func (s *stepCreateZpool) CleanupFunc(state multistep.StateBag) error {
+	if s.pool == "" {
+		return nil
+	}
+
+	ui := state.Get("ui").(packer.Ui)
+	ui.Say(fmt.Sprintf("Exporting ZFS pool %s...", s.pool))
+
+	pool := shellQuote(s.pool)
+	command := fmt.Sprintf("! zpool list %s >/dev/null 2>&1 || zpool export %s", pool, pool)
+	if _, err := runRemoteCommand(context.TODO(), state, command); err != nil {
+		return fmt.Errorf("Error exporting ZFS pool: %s", err)
+	}
+
+	s.pool = ""
+	return nil
+}
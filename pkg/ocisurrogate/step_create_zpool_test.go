@@ -0,0 +1,59 @@
+package ocisurrogate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepCreateZpool(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateLayout = testZFSLayout()
+	config.SurrogateLayout.Prepare()
+	config.ZFSRoot.Prepare(&config.SurrogateLayout)
+	state.Put("surrogate_device", "/dev/sdb")
+
+	step := new(stepCreateZpool)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if root := state.Get("zfs_root_dataset").(string); root != "rpool/ROOT/default" {
+		t.Fatalf("bad zfs_root_dataset: %s", root)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	comm.Commands = nil
+	if err := state.Get("zpool_cleanup").(Cleanup).CleanupFunc(state); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := "! zpool list 'rpool' >/dev/null 2>&1 || zpool export 'rpool'"
+	if len(comm.Commands) != 1 || comm.Commands[0] != expected {
+		t.Fatalf("bad export commands: %v", comm.Commands)
+	}
+
+	comm.Commands = nil
+	step.Cleanup(state)
+	if len(comm.Commands) != 0 {
+		t.Fatalf("should not export twice, got %v", comm.Commands)
+	}
+}
+
+func TestStepCreateZpool_Disabled(t *testing.T) {
+	state := testState()
+
+	step := new(stepCreateZpool)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if comm := state.Get("communicator").(*commandRecorder); len(comm.Commands) != 0 {
+		t.Fatalf("should not run any command, got %v", comm.Commands)
+	}
+}
@@ -0,0 +1,40 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepDetachSurrogateVolumes detaches the surrogate_volumes from the builder
+// instance so that they can be attached to the surrogate instance at launch.
+type stepDetachSurrogateVolumes struct{}
+
+func (s *stepDetachSurrogateVolumes) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+	)
+
+	volumesRaw, ok := state.GetOk("surrogate_volumes")
+	if !ok {
+		return multistep.ActionContinue
+	}
+
+	for _, volume := range volumesRaw.([]*createdVolume) {
+		if err := detachVolume(ctx, driver, ui, volume); err != nil {
+			err = fmt.Errorf("Problem detaching surrogate volume from the builder instance: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepDetachSurrogateVolumes) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
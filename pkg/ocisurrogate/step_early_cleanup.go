@@ -0,0 +1,50 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// Cleanup is implemented by steps whose cleanup has to happen before the
+// surrogate volume is detached from the builder instance.
+type Cleanup interface {
+	CleanupFunc(multistep.StateBag) error
+}
+
+// stepEarlyCleanup releases everything that still holds on to the surrogate
+// volume so that it can be detached and booted as the surrogate instance.
+type stepEarlyCleanup struct{}
+
+func (s *stepEarlyCleanup) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+	cleanupKeys := []string{
+		"chroot_mounts_cleanup",
+		"mount_surrogate_cleanup",
+		"zpool_cleanup",
+		"iscsi_cleanup",
+	}
+
+	for _, key := range cleanupKeys {
+		raw, ok := state.GetOk(key)
+		if !ok {
+			continue
+		}
+		log.Printf("Running cleanup func: %s", key)
+		if err := raw.(Cleanup).CleanupFunc(state); err != nil {
+			err = fmt.Errorf("Error cleaning up: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepEarlyCleanup) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
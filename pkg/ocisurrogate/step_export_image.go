@@ -0,0 +1,119 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/packer/builder"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepExportImage exports the image to Object Storage when export is set,
+// and sets:
+//
+//	export_url         string - URL of the exported object
+//	export_object_name string - Name of the exported object
+//	export_publish_url string - URL publishing the object, with publish_duration
+//
+// and the ExportURL and ExportPublishURL generated data. The image is
+// deleted by stepImage when the export fails, and the publishing
+// pre-authenticated request when the build fails afterwards.
+type stepExportImage struct {
+	publishID string
+}
+
+func (s *stepExportImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		image  = state.Get("image").(core.Image)
+		region = state.Get("region").(string)
+		export = &config.Export
+	)
+
+	if !export.isSet() {
+		return multistep.ActionContinue
+	}
+
+	objectName, err := export.objectName(config.ctx, config.ImageName, *image.Id)
+	if err != nil {
+		err = fmt.Errorf("Error rendering export object_name: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say(fmt.Sprintf("Exporting image to %s/%s...", export.Bucket, objectName))
+
+	workRequestID, err := driver.ExportImage(ctx, *image.Id, export, objectName)
+	if err != nil {
+		err = fmt.Errorf("Error exporting image: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say(fmt.Sprintf("Waiting for export work request %s to succeed...", workRequestID))
+
+	if err := driver.WaitForWorkRequest(ctx, workRequestID); err != nil {
+		err = fmt.Errorf("Error waiting for image export: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	url := export.objectURL(region, objectName)
+	state.Put("export_url", url)
+	state.Put("export_object_name", objectName)
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("ExportURL", url)
+
+	ui.Say(fmt.Sprintf("Image exported to %s.", url))
+
+	if export.PublishDuration == 0 {
+		return multistep.ActionContinue
+	}
+
+	expires := time.Now().Add(export.PublishDuration)
+	accessURI, id, err := driver.CreatePreauthenticatedRequest(ctx, export, objectName, objectName+"-publish", expires)
+	if err != nil {
+		err = fmt.Errorf("Error publishing exported image: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	s.publishID = id
+
+	publishURL := preauthenticatedURL(region, accessURI)
+	state.Put("export_publish_url", publishURL)
+	generatedData.Put("ExportPublishURL", publishURL)
+
+	ui.Say(fmt.Sprintf("Exported image published until %s.", expires.Format(time.RFC3339)))
+
+	return multistep.ActionContinue
+}
+
+func (s *stepExportImage) Cleanup(state multistep.StateBag) {
+	if s.publishID == "" {
+		return
+	}
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	_, halted := state.GetOk(multistep.StateHalted)
+	if !cancelled && !halted {
+		return
+	}
+
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	if err := driver.DeletePreauthenticatedRequest(context.TODO(), &config.Export, s.publishID); err != nil {
+		ui.Error(fmt.Sprintf("Error deleting pre-authenticated request %s: %s", s.publishID, err))
+	}
+}
@@ -0,0 +1,125 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepExportImage(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	state.Put("region", "us-ashburn-1")
+	config := state.Get("config").(*Config)
+	config.Export = ImageExport{Bucket: "images", Namespace: "ns", ObjectName: "{{.ImageName}}-{{.ImageOCID}}"}
+
+	step := new(stepExportImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if len(driver.ExportImageObjectNames) != 1 || driver.ExportImageObjectNames[0] != "HelloWorld-ocid1.image" {
+		t.Fatalf("bad exported objects: %v", driver.ExportImageObjectNames)
+	}
+	expected := "https://objectstorage.us-ashburn-1.oraclecloud.com/n/ns/b/images/o/HelloWorld-ocid1.image"
+	if url := state.Get("export_url"); url != expected {
+		t.Fatalf("bad export_url: %v", url)
+	}
+	if name := state.Get("export_object_name"); name != "HelloWorld-ocid1.image" {
+		t.Fatalf("bad export_object_name: %v", name)
+	}
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if url := generatedData["ExportURL"]; url != expected {
+		t.Fatalf("bad ExportURL generated data: %v", url)
+	}
+}
+
+func TestStepExportImage_Publish(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	state.Put("region", "us-ashburn-1")
+	config := state.Get("config").(*Config)
+	config.Export = ImageExport{Bucket: "images", Namespace: "ns", ObjectName: "{{.ImageName}}.oci", PublishDuration: 24 * time.Hour}
+
+	step := new(stepExportImage)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if len(driver.PreauthenticatedRequestNames) != 1 || driver.PreauthenticatedRequestNames[0] != "HelloWorld.oci-publish" {
+		t.Fatalf("bad pre-authenticated requests: %v", driver.PreauthenticatedRequestNames)
+	}
+	expected := "https://objectstorage.us-ashburn-1.oraclecloud.com/p/secret/n/ns/b/images/o/HelloWorld.oci"
+	if url := state.Get("export_publish_url"); url != expected {
+		t.Fatalf("bad export_publish_url: %v", url)
+	}
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if url := generatedData["ExportPublishURL"]; url != expected {
+		t.Fatalf("bad ExportPublishURL generated data: %v", url)
+	}
+
+	step.Cleanup(state)
+	if driver.DeletePreauthenticatedRequestID != "" {
+		t.Fatalf("should keep the pre-authenticated request of a successful build")
+	}
+
+	state.Put(multistep.StateHalted, true)
+	step.Cleanup(state)
+	if driver.DeletePreauthenticatedRequestID != "ocid1.par.HelloWorld.oci-publish" {
+		t.Fatalf("bad deleted pre-authenticated request: %s", driver.DeletePreauthenticatedRequestID)
+	}
+}
+
+func TestStepExportImage_Unset(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	state.Put("region", "us-ashburn-1")
+
+	step := new(stepExportImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if len(driver.ExportImageObjectNames) != 0 {
+		t.Fatalf("should not have exported the image")
+	}
+}
+
+func TestStepExportImage_WaitForWorkRequestErr(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	state.Put("region", "us-ashburn-1")
+	config := state.Get("config").(*Config)
+	config.Export = ImageExport{Bucket: "images", Namespace: "ns", ObjectName: "image"}
+
+	step := new(stepExportImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.WaitForWorkRequestErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("export_url"); ok {
+		t.Fatalf("should NOT have export_url")
+	}
+}
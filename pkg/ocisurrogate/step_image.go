@@ -6,63 +6,22 @@ import (
 
 	"github.com/hashicorp/packer/helper/multistep"
 	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
+// stepImage creates the image from the surrogate instance. The image is
+// deleted if the build halts or is cancelled afterwards, e.g. when its
+// creation does not complete or when setting its capabilities or exporting
+// it fails.
 type stepImage struct{}
 
 func (s *stepImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
 	var (
-		driver     = state.Get("driver").(Driver)
-		ui         = state.Get("ui").(packer.Ui)
-		idVolume   = state.Get("cloned_volume_id").(string)
-		attachedVolumeID   = state.Get("attached_volume_id").(string)
-		config = state.Get("config").(*Config)
+		driver              = state.Get("driver").(Driver)
+		ui                  = state.Get("ui").(packer.Ui)
+		instanceSurrogateID = state.Get("instance_surrogate_id").(string)
 	)
 
-	ui.Say("Detaching Boot Volume from main instance...")
-    detachedVolumeID, err := driver.DetachBootClone(ctx, attachedVolumeID)
-	if err != nil {
-		err = fmt.Errorf("Problem Detaching Boot Volume Clone: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
-		return multistep.ActionHalt
-	}
-	ui.Say(fmt.Sprintf("Surrogate Boot Volume Detachment request created for %s.",detachedVolumeID))
-	ui.Say(fmt.Sprintf("Waiting for Attached Volume %s to enter 'DETACHED' state...",attachedVolumeID))
-	if err = driver.WaitForVolumeAttachmentState(ctx, attachedVolumeID, []string{"DETACHING"}, "DETACHED"); err != nil {
-		err = fmt.Errorf("Error waiting for Volume to be detached: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
-		return multistep.ActionHalt
-	}
-
-	ui.Say("Cloned Volume detached...")
-	ui.Say("Creating Surrogate instance...")
-
-	instanceSurrogateID, err := driver.CreateInstance(ctx, string(config.Comm.SSHPublicKey), idVolume)
-	if err != nil {
-		err = fmt.Errorf("Problem creating surrogate instance: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
-		return multistep.ActionHalt
-	}
-
-	state.Put("instance_surrogate_id", instanceSurrogateID)
-
-	ui.Say(fmt.Sprintf("Created Surrogate instance (%s).", instanceSurrogateID))
-
-	ui.Say("Waiting for Surrogate instance to enter 'RUNNING' state...")
-
-	if err = driver.WaitForInstanceState(ctx, instanceSurrogateID, []string{"STARTING", "PROVISIONING"}, "RUNNING"); err != nil {
-		err = fmt.Errorf("Error waiting for instance to start: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
-		return multistep.ActionHalt
-	}
-
-	ui.Say("Surrogate Instance 'RUNNING'.")
-
-
 	ui.Say("Creating image from Surrogate instance...")
 
 	image, err := driver.CreateImage(ctx, instanceSurrogateID)
@@ -73,6 +32,12 @@ func (s *stepImage) Run(ctx context.Context, state multistep.StateBag) multistep
 		return multistep.ActionHalt
 	}
 
+	// The image is registered before it is available so that Cleanup
+	// deletes it if the wait fails.
+	// TODO(apryde): This is stale as .LifecycleState has changed to
+	// AVAILABLE at this point. Does it matter?
+	state.Put("image", image)
+
 	err = driver.WaitForImageCreation(ctx, *image.Id)
 	if err != nil {
 		err = fmt.Errorf("Error waiting for image creation to finish: %s", err)
@@ -81,41 +46,32 @@ func (s *stepImage) Run(ctx context.Context, state multistep.StateBag) multistep
 		return multistep.ActionHalt
 	}
 
-	// TODO(apryde): This is stale as .LifecycleState has changed to
-	// AVAILABLE at this point. Does it matter?
-	state.Put("image", image)
-
 	ui.Say("Image created.")
 
 	return multistep.ActionContinue
 }
 
 func (s *stepImage) Cleanup(state multistep.StateBag) {
-	driver := state.Get("driver").(Driver)
-	ui := state.Get("ui").(packer.Ui)
-
-	idRaw, ok := state.GetOk("instance_surrogate_id")
+	rawImage, ok := state.GetOk("image")
 	if !ok {
 		return
 	}
-	id := idRaw.(string)
-
-	ui.Say(fmt.Sprintf("Terminating instance (%s)...", id))
-
-	if err := driver.TerminateInstance(context.TODO(), id); err != nil {
-		err = fmt.Errorf("Error terminating instance. Please terminate manually: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	_, halted := state.GetOk(multistep.StateHalted)
+	if !cancelled && !halted {
 		return
 	}
 
-	err := driver.WaitForInstanceState(context.TODO(), id, []string{"TERMINATING"}, "TERMINATED")
-	if err != nil {
-		err = fmt.Errorf("Error terminating instance. Please terminate manually: %s", err)
-		ui.Error(err.Error())
-		state.Put("error", err)
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		image  = rawImage.(core.Image)
+	)
+
+	ui.Say(fmt.Sprintf("Deleting image %s of the failed build...", *image.Id))
+	if err := driver.DeleteImage(context.TODO(), *image.Id); err != nil {
+		ui.Error(fmt.Sprintf("Error deleting image %s, you may need to delete it manually: %s", *image.Id, err))
 		return
 	}
-
-	ui.Say("Terminated instance.")
+	ui.Say("Image deleted.")
 }
@@ -0,0 +1,62 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepImageCapabilities adds image_compatible_shapes to the shapes the image
+// is compatible with and sets its image_capabilities. The results are
+// recorded in the artifact through the state:
+//
+//	image_compatible_shapes    []string - Shapes added to the image
+//	image_capability_schema_id string   - OCID of the capability schema
+type stepImageCapabilities struct{}
+
+func (s *stepImageCapabilities) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		image  = state.Get("image").(core.Image)
+	)
+
+	for _, shape := range config.ImageCompatibleShapes {
+		ui.Say(fmt.Sprintf("Making image compatible with shape %s...", shape))
+		if err := driver.AddImageShapeCompatibility(ctx, *image.Id, shape); err != nil {
+			err = fmt.Errorf("Error adding shape %s to image %s: %s", shape, *image.Id, err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+	if len(config.ImageCompatibleShapes) != 0 {
+		state.Put("image_compatible_shapes", config.ImageCompatibleShapes)
+	}
+
+	if len(config.ImageCapabilities) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say("Setting image capabilities...")
+	id, err := driver.CreateImageCapabilitySchema(ctx, *image.Id, config.ImageCapabilities)
+	if err != nil {
+		err = fmt.Errorf("Error setting capabilities of image %s: %s", *image.Id, err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	state.Put("image_capability_schema_id", id)
+
+	ui.Say(fmt.Sprintf("Image capability schema created (%s).", id))
+
+	return multistep.ActionContinue
+}
+
+func (s *stepImageCapabilities) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
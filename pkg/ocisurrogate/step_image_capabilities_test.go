@@ -0,0 +1,72 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepImageCapabilities(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageCompatibleShapes = []string{"VM.Standard.E3.Flex", "BM.Standard2.52"}
+	config.ImageCapabilities = map[string][]string{"Compute.Firmware": {"UEFI_64"}}
+
+	step := new(stepImageCapabilities)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if !reflect.DeepEqual(driver.ImageCompatibleShapes, config.ImageCompatibleShapes) {
+		t.Errorf("bad compatible shapes: %v", driver.ImageCompatibleShapes)
+	}
+	if !reflect.DeepEqual(driver.ImageCapabilities, config.ImageCapabilities) {
+		t.Errorf("bad capabilities: %v", driver.ImageCapabilities)
+	}
+	if _, ok := state.GetOk("image_capability_schema_id"); !ok {
+		t.Fatalf("should have image_capability_schema_id")
+	}
+}
+
+func TestStepImageCapabilities_AddImageShapeCompatibilityErr(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageCompatibleShapes = []string{"VM.Standard.E3.Flex"}
+
+	step := new(stepImageCapabilities)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.AddImageShapeCompatibilityErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
+
+func TestCapabilityDescriptor(t *testing.T) {
+	if d, ok := capabilityDescriptor([]string{"true"}).(core.BooleanImageCapabilitySchemaDescriptor); !ok || !*d.DefaultValue {
+		t.Errorf("expected a true boolean descriptor, got %#v", d)
+	}
+	if d, ok := capabilityDescriptor([]string{"2", "1"}).(core.EnumIntegerImageCapabilityDescriptor); !ok || *d.DefaultValue != 2 || len(d.Values) != 2 {
+		t.Errorf("expected an enuminteger descriptor defaulting to 2, got %#v", d)
+	}
+	if d, ok := capabilityDescriptor([]string{"PARAVIRTUALIZED", "ISCSI"}).(core.EnumStringImageCapabilitySchemaDescriptor); !ok || *d.DefaultValue != "PARAVIRTUALIZED" {
+		t.Errorf("expected an enumstring descriptor defaulting to PARAVIRTUALIZED, got %#v", d)
+	}
+}
@@ -0,0 +1,96 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/builder"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepImageFamily deprecates the previous newest image of the family of the
+// image, when image_family is set, and sets:
+//
+//	previous_image_id string - OCID of the image deprecated
+//
+// and the PreviousImageOCID generated data. The previous image gets its
+// defined tags back if the build halts or is cancelled afterwards.
+type stepImageFamily struct {
+	previous *core.Image
+}
+
+func (s *stepImageFamily) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		image  = state.Get("image").(core.Image)
+		family = &config.ImageFamily
+	)
+
+	if !family.isSet() {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Looking up the previous image of family %s...", family.Name))
+
+	images, err := driver.ListImages(ctx, family.filter())
+	if err != nil {
+		err = fmt.Errorf("Error listing the images of family %s: %s", family.Name, err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	previous, ok := family.previous(images, *image.Id)
+	if !ok {
+		ui.Say(fmt.Sprintf("Image is the first of family %s.", family.Name))
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Deprecating image %s...", *previous.Id))
+
+	if err := driver.UpdateImageDefinedTags(ctx, *previous.Id, family.deprecatedTags(previous)); err != nil {
+		err = fmt.Errorf("Error deprecating image %s: %s", *previous.Id, err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	s.previous = &previous
+
+	state.Put("previous_image_id", *previous.Id)
+	(&builder.GeneratedData{State: state}).Put("PreviousImageOCID", *previous.Id)
+
+	ui.Say("Image deprecated.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepImageFamily) Cleanup(state multistep.StateBag) {
+	if s.previous == nil {
+		return
+	}
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	_, halted := state.GetOk(multistep.StateHalted)
+	if !cancelled && !halted {
+		return
+	}
+
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		tags   = s.previous.DefinedTags
+	)
+
+	if tags == nil {
+		// an empty map, unlike nil, removes the tags
+		tags = map[string]map[string]interface{}{}
+	}
+
+	ui.Say(fmt.Sprintf("Restoring the tags of deprecated image %s...", *s.previous.Id))
+	if err := driver.UpdateImageDefinedTags(context.TODO(), *s.previous.Id, tags); err != nil {
+		ui.Error(fmt.Sprintf("Error restoring the tags of image %s, you may need to remove its deprecated tag manually: %s", *s.previous.Id, err))
+	}
+}
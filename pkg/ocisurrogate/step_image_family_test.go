@@ -0,0 +1,104 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepImageFamily(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageFamily = ImageFamily{Name: "ol8-base", DeprecatedTagNamespace: "Images"}
+	config.ImageFamily.Prepare("ocid1.compartment")
+
+	step := new(stepImageFamily)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testFamilyImages(time.Now())
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if driver.UpdateImageDefinedTagsID != "ocid1.image.2" {
+		t.Fatalf("bad deprecated image: %s", driver.UpdateImageDefinedTagsID)
+	}
+	if driver.UpdateImageDefinedTags["Images"]["deprecated"] != "true" {
+		t.Fatalf("bad defined tags: %v", driver.UpdateImageDefinedTags)
+	}
+	if previous := state.Get("previous_image_id"); previous != "ocid1.image.2" {
+		t.Fatalf("bad previous_image_id: %v", previous)
+	}
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if previous := generatedData["PreviousImageOCID"]; previous != "ocid1.image.2" {
+		t.Fatalf("bad PreviousImageOCID generated data: %v", previous)
+	}
+
+	step.Cleanup(state)
+	if _, ok := driver.UpdateImageDefinedTags["Images"]; !ok {
+		t.Fatalf("should keep the previous image deprecated after a successful build")
+	}
+
+	state.Put(multistep.StateHalted, true)
+	step.Cleanup(state)
+	expected := map[string]map[string]interface{}{"Operations": {"CostCenter": "42"}}
+	if !reflect.DeepEqual(driver.UpdateImageDefinedTags, expected) {
+		t.Fatalf("should restore the defined tags of the previous image, got %v", driver.UpdateImageDefinedTags)
+	}
+}
+
+func TestStepImageFamily_First(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageFamily = ImageFamily{Name: "ol9-base", DeprecatedTagNamespace: "Images"}
+	config.ImageFamily.Prepare("ocid1.compartment")
+
+	step := new(stepImageFamily)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testFamilyImages(time.Now())
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if driver.UpdateImageDefinedTagsID != "" {
+		t.Fatalf("should not deprecate any image")
+	}
+	if _, ok := state.GetOk("previous_image_id"); ok {
+		t.Fatalf("should NOT have previous_image_id")
+	}
+}
+
+func TestStepImageFamily_UpdateErr(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageFamily = ImageFamily{Name: "ol8-base", DeprecatedTagNamespace: "Images"}
+	config.ImageFamily.Prepare("ocid1.compartment")
+
+	step := new(stepImageFamily)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testFamilyImages(time.Now())
+	driver.UpdateImageDefinedTagsErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 func TestStepImage(t *testing.T) {
 	state := testState()
-	state.Put("instance_id", "ocid1...")
+	state.Put("instance_surrogate_id", "ocid1...")
 
 	step := new(stepImage)
 	defer step.Cleanup(state)
@@ -26,7 +26,7 @@ func TestStepImage(t *testing.T) {
 
 func TestStepImage_CreateImageErr(t *testing.T) {
 	state := testState()
-	state.Put("instance_id", "ocid1...")
+	state.Put("instance_surrogate_id", "ocid1...")
 
 	step := new(stepImage)
 	defer step.Cleanup(state)
@@ -49,7 +49,7 @@ func TestStepImage_CreateImageErr(t *testing.T) {
 
 func TestStepImage_WaitForImageCreationErr(t *testing.T) {
 	state := testState()
-	state.Put("instance_id", "ocid1...")
+	state.Put("instance_surrogate_id", "ocid1...")
 
 	step := new(stepImage)
 	defer step.Cleanup(state)
@@ -65,7 +65,37 @@ func TestStepImage_WaitForImageCreationErr(t *testing.T) {
 		t.Fatalf("should have error")
 	}
 
-	if _, ok := state.GetOk("image"); ok {
-		t.Fatalf("should not have image")
+	if _, ok := state.GetOk("image"); !ok {
+		t.Fatalf("should have registered the image before waiting for it")
+	}
+
+	state.Put(multistep.StateHalted, true)
+	step.Cleanup(state)
+	if driver.DeleteImageID != "ocid1..." {
+		t.Fatalf("should delete the image that did not become available, got %q", driver.DeleteImageID)
+	}
+}
+
+func TestStepImage_CleanupHalted(t *testing.T) {
+	state := testState()
+	state.Put("instance_surrogate_id", "ocid1...")
+
+	step := new(stepImage)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+
+	step.Cleanup(state)
+	if driver.DeleteImageID != "" {
+		t.Fatalf("should NOT delete the image of a successful build")
+	}
+
+	state.Put(multistep.StateHalted, true)
+	step.Cleanup(state)
+	if driver.DeleteImageID != "ocid1..." {
+		t.Fatalf("should delete the image of a halted build, got %q", driver.DeleteImageID)
 	}
 }
@@ -0,0 +1,103 @@
+package ocisurrogate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepInstallBootloader configures the kernel command line of the surrogate,
+// regenerates its grub.cfg and initramfs and installs GRUB to the surrogate
+// device, all from inside the chroot.
+type stepInstallBootloader struct{}
+
+func (s *stepInstallBootloader) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		b      = &config.Bootloader
+	)
+
+	if b.Firmware == "" {
+		return multistep.ActionContinue
+	}
+
+	device := b.InstallDevice
+	if device == "" {
+		device = surrogateDevice(state)
+	}
+
+	ui.Say(fmt.Sprintf("Installing %s bootloader on %s...", b.Firmware, device))
+
+	comm := newChrootCommunicator(state)
+
+	ui.Message("Updating /etc/default/grub...")
+	if err := editChrootFile(comm, "/etc/default/grub", b.updateGrubDefaults); err != nil {
+		return s.halt(state, err)
+	}
+
+	var commands []string
+	if config.ZFSRoot.enabled {
+		commands = append(commands, fmt.Sprintf("zpool set cachefile= %s", shellQuote(config.ZFSRoot.PoolName)))
+	}
+	commands = append(commands, fmt.Sprintf("grub2-mkconfig -o %s", shellQuote(b.GrubConfig)))
+	for _, command := range commands {
+		ui.Message(command)
+		if err := runCommand(ctx, comm, command, ioutil.Discard); err != nil {
+			return s.halt(state, err)
+		}
+	}
+
+	ui.Message(fmt.Sprintf("Patching %s...", b.GrubConfig))
+	if err := editChrootFile(comm, b.GrubConfig, b.patchGrubConfig); err != nil {
+		return s.halt(state, err)
+	}
+
+	commands = nil
+	if b.RegenerateInitramfs {
+		commands = append(commands, "dracut -f --regenerate-all")
+	}
+	commands = append(commands, b.grubInstallCommand(device))
+	for _, command := range commands {
+		ui.Message(command)
+		if err := runCommand(ctx, comm, command, ioutil.Discard); err != nil {
+			return s.halt(state, err)
+		}
+	}
+
+	ui.Say("Bootloader installed.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepInstallBootloader) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
+
+func (s *stepInstallBootloader) halt(state multistep.StateBag, err error) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+
+	err = fmt.Errorf("Error installing bootloader: %s", err)
+	ui.Error(err.Error())
+	state.Put("error", err)
+	return multistep.ActionHalt
+}
+
+// editChrootFile replaces the content of path inside the chroot with the
+// result of edit.
+func editChrootFile(comm *chrootCommunicator, path string, edit func(string) string) error {
+	var buf bytes.Buffer
+	if err := comm.Download(path, &buf); err != nil {
+		return fmt.Errorf("Error downloading %s: %s", path, err)
+	}
+
+	if err := comm.Upload(path, strings.NewReader(edit(buf.String())), nil); err != nil {
+		return fmt.Errorf("Error uploading %s: %s", path, err)
+	}
+	return nil
+}
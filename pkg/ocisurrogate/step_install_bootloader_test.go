@@ -0,0 +1,71 @@
+package ocisurrogate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepInstallBootloader(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+	state.Put("surrogate_device", "/dev/sdb")
+	config := state.Get("config").(*Config)
+	config.ZFSRoot = ZFSRoot{PoolName: "rpool", enabled: true}
+	config.Bootloader = Bootloader{
+		Firmware:            "uefi",
+		GrubConfig:          "/boot/efi/EFI/redhat/grub.cfg",
+		EFIDirectory:        "/boot/efi",
+		GrubRoot:            "hd0,gpt2",
+		RegenerateInitramfs: true,
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	comm.DownloadData = "\tset root='hd0,gpt3'\n"
+
+	step := new(stepInstallBootloader)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	var chrooted []string
+	for _, command := range comm.Commands {
+		if strings.HasPrefix(command, "chroot '/mnt' ") {
+			chrooted = append(chrooted, command)
+		}
+	}
+	expected := []string{
+		"chroot '/mnt' /bin/sh -c 'zpool set cachefile= '\"'\"'rpool'\"'\"''",
+		"chroot '/mnt' /bin/sh -c 'grub2-mkconfig -o '\"'\"'/boot/efi/EFI/redhat/grub.cfg'\"'\"''",
+		"chroot '/mnt' /bin/sh -c 'dracut -f --regenerate-all'",
+		"chroot '/mnt' /bin/sh -c 'grub2-install --target=x86_64-efi --efi-directory='\"'\"'/boot/efi'\"'\"' --no-nvram " +
+			"-d /usr/lib/grub/x86_64-efi '\"'\"'/dev/sdb'\"'\"''",
+	}
+	if strings.Join(chrooted, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("bad chroot commands:\n got: %v\nwant: %v", chrooted, expected)
+	}
+
+	if comm.UploadData != "\tset root='hd0,gpt2'\n" {
+		t.Errorf("bad grub.cfg upload: %q", comm.UploadData)
+	}
+}
+
+func TestStepInstallBootloader_Disabled(t *testing.T) {
+	state := testState()
+	state.Put("mount_path", "/mnt")
+
+	step := new(stepInstallBootloader)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if comm := state.Get("communicator").(*commandRecorder); len(comm.Commands) != 0 {
+		t.Errorf("expected no commands, got %v", comm.Commands)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/packer/builder"
 	"github.com/hashicorp/packer/helper/multistep"
 	"github.com/hashicorp/packer/packer"
 )
@@ -27,6 +28,9 @@ func (s *stepInstanceInfo) Run(ctx context.Context, state multistep.StateBag) mu
 
 	state.Put("instance_ip", ip)
 
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("InstanceIP", ip)
+
 	ui.Say(fmt.Sprintf("Instance has IP: %s.", ip))
 
 	return multistep.ActionContinue
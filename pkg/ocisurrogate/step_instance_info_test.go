@@ -29,6 +29,11 @@ func TestInstanceInfo(t *testing.T) {
 	if instanceIPRaw.(string) != "ip" {
 		t.Fatalf("should've got ip ('%s' != 'ip')", instanceIPRaw.(string))
 	}
+
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if generatedData["InstanceIP"] != "ip" {
+		t.Fatalf("bad InstanceIP: %v", generatedData["InstanceIP"])
+	}
 }
 
 func TestInstanceInfoPrivateIP(t *testing.T) {
@@ -0,0 +1,99 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepISCSILogin logs the builder instance in to the iSCSI target of the
+// surrogate volume, when it is attached with the iscsi attachment_type, so
+// that its device appears.
+//
+// Produces:
+//
+//	iscsi_cleanup  Cleanup - To perform early cleanup
+type stepISCSILogin struct {
+	target *iscsiTarget
+}
+
+func (s *stepISCSILogin) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	ui := state.Get("ui").(packer.Ui)
+
+	targetRaw, ok := state.GetOk("surrogate_iscsi_target")
+	if !ok {
+		return multistep.ActionContinue
+	}
+	target := targetRaw.(*iscsiTarget)
+
+	ui.Say(fmt.Sprintf("Logging in to iSCSI target %s...", target.IQN))
+
+	node := target.node()
+	commands := []string{
+		fmt.Sprintf("iscsiadm -m node -o new %s", node),
+		fmt.Sprintf("iscsiadm -m node -o update %s -n node.startup -v automatic", node),
+		fmt.Sprintf("iscsiadm -m node %s -l", node),
+	}
+	for i, command := range commands {
+		ui.Message(command)
+		if _, err := runRemoteCommand(ctx, state, command); err != nil {
+			err = fmt.Errorf("Error logging in to iSCSI target: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+		if i == 0 {
+			s.target = target
+			state.Put("iscsi_cleanup", s)
+		}
+	}
+
+	device := state.Get("surrogate_device").(string)
+	command := fmt.Sprintf("udevadm settle && test -b %s", device)
+	if _, err := runRemoteCommand(ctx, state, command); err != nil {
+		err = fmt.Errorf("Device %s did not appear after the iSCSI login: %s", device, err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say("Logged in to iSCSI target.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepISCSILogin) Cleanup(state multistep.StateBag) {
+	ui := state.Get("ui").(packer.Ui)
+
+	if err := s.CleanupFunc(state); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// CleanupFunc logs out of the iSCSI target and removes its node record, so
+// that the surrogate volume can be detached. It is safe to call more than
+// once.
+func (s *stepISCSILogin) CleanupFunc(state multistep.StateBag) error {
+	if s.target == nil {
+		return nil
+	}
+
+	ui := state.Get("ui").(packer.Ui)
+	ui.Say(fmt.Sprintf("Logging out of iSCSI target %s...", s.target.IQN))
+
+	node := s.target.node()
+	command := fmt.Sprintf("iscsiadm -m node %s -u; iscsiadm -m node -o delete %s", node, node)
+	if _, err := runRemoteCommand(context.TODO(), state, command); err != nil {
+		return fmt.Errorf("Error logging out of iSCSI target: %s", err)
+	}
+
+	s.target = nil
+	return nil
+}
+
+// node returns the iscsiadm arguments selecting the node record of t.
+func (t *iscsiTarget) node() string {
+	return fmt.Sprintf("-T %s -p %s:%d", t.IQN, t.IPv4, t.Port)
+}
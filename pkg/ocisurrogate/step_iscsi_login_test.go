@@ -0,0 +1,60 @@
+package ocisurrogate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepISCSILogin(t *testing.T) {
+	state := testState()
+	state.Put("surrogate_device", "/dev/sdb")
+	state.Put("surrogate_iscsi_target", &iscsiTarget{
+		IQN:  "iqn.2015-12.com.oracleiaas:abcd",
+		IPv4: "169.254.2.2",
+		Port: 3260,
+	})
+
+	step := new(stepISCSILogin)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	expected := "iscsiadm -m node -T iqn.2015-12.com.oracleiaas:abcd -p 169.254.2.2:3260 -l"
+	if len(comm.Commands) != 4 || comm.Commands[2] != expected {
+		t.Fatalf("bad login commands:\n got: %v\nwant: %s", comm.Commands, expected)
+	}
+
+	comm.Commands = nil
+	if err := state.Get("iscsi_cleanup").(Cleanup).CleanupFunc(state); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(comm.Commands) != 1 {
+		t.Fatalf("bad logout commands: %v", comm.Commands)
+	}
+
+	comm.Commands = nil
+	step.Cleanup(state)
+	if len(comm.Commands) != 0 {
+		t.Fatalf("should not log out twice, got %v", comm.Commands)
+	}
+}
+
+func TestStepISCSILogin_Paravirtualized(t *testing.T) {
+	state := testState()
+
+	step := new(stepISCSILogin)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("iscsi_cleanup"); ok {
+		t.Fatalf("should NOT have iscsi_cleanup")
+	}
+}
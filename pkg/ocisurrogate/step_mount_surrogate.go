@@ -0,0 +1,94 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepMountSurrogate mounts the filesystems of surrogate_layout under
+// mount_path on the builder instance.
+//
+// Produces:
+//
+//	mount_path               string  - Root of the mounted surrogate
+//	mount_surrogate_cleanup  Cleanup - To perform early cleanup
+type stepMountSurrogate struct {
+	mounts []string
+}
+
+func (s *stepMountSurrogate) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		layout = &config.SurrogateLayout
+	)
+
+	state.Put("mount_path", config.MountPath)
+
+	mounts := layout.mounts(surrogateDevice(state))
+	if len(mounts) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Mounting surrogate volume under %s...", config.MountPath))
+
+	s.mounts = make([]string, 0, len(mounts))
+	state.Put("mount_surrogate_cleanup", s)
+
+	for _, m := range mounts {
+		target := path.Join(config.MountPath, m.MountPoint)
+		ui.Message(fmt.Sprintf("Mounting %s on %s", m.Device, target))
+
+		command := fmt.Sprintf("mkdir -p %s && mount %s %s",
+			shellQuote(target), shellQuote(m.Device), shellQuote(target))
+		if _, err := runRemoteCommand(ctx, state, command); err != nil {
+			err = fmt.Errorf("Error mounting surrogate volume: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+
+		s.mounts = append(s.mounts, target)
+	}
+
+	return multistep.ActionContinue
+}
+
+// checkSurrogateMounted returns an error unless a filesystem is mounted on
+// mountPath, so that the builder instance's own root is never written to
+// in its place.
+func checkSurrogateMounted(ctx context.Context, state multistep.StateBag, mountPath string) error {
+	if _, err := runRemoteCommand(ctx, state, "mountpoint -q "+shellQuote(mountPath)); err != nil {
+		return fmt.Errorf("no surrogate filesystem is mounted on %s: %s", mountPath, err)
+	}
+	return nil
+}
+
+func (s *stepMountSurrogate) Cleanup(state multistep.StateBag) {
+	ui := state.Get("ui").(packer.Ui)
+
+	if err := s.CleanupFunc(state); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// CleanupFunc unmounts the surrogate filesystems in reverse mount order. It
+// is safe to call more than once.
+func (s *stepMountSurrogate) CleanupFunc(state multistep.StateBag) error {
+	for len(s.mounts) > 0 {
+		var target string
+		lastIndex := len(s.mounts) - 1
+		target, s.mounts = s.mounts[lastIndex], s.mounts[:lastIndex]
+
+		command := fmt.Sprintf("! mountpoint -q %s || umount %s", shellQuote(target), shellQuote(target))
+		if _, err := runRemoteCommand(context.TODO(), state, command); err != nil {
+			return fmt.Errorf("Error unmounting surrogate volume: %s", err)
+		}
+	}
+
+	return nil
+}
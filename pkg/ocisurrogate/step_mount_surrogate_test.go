@@ -0,0 +1,84 @@
+package ocisurrogate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepMountSurrogate(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateLayout = testSurrogateLayout()
+	config.SurrogateLayout.Prepare()
+	state.Put("surrogate_device", "/dev/sdb")
+
+	step := new(stepMountSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	if len(comm.Commands) != 3 {
+		t.Fatalf("expected 3 mount commands, got %v", comm.Commands)
+	}
+	if expected := "mkdir -p '/mnt' && mount '/dev/sdb3' '/mnt'"; comm.Commands[0] != expected {
+		t.Errorf("bad mount command:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+
+	if _, ok := state.GetOk("mount_surrogate_cleanup"); !ok {
+		t.Fatalf("should have mount_surrogate_cleanup")
+	}
+
+	comm.Commands = nil
+	if err := step.CleanupFunc(state); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := "! mountpoint -q '/mnt/boot/efi' || umount '/mnt/boot/efi'"; comm.Commands[0] != expected {
+		t.Errorf("bad unmount command:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+
+	comm.Commands = nil
+	step.Cleanup(state)
+	if len(comm.Commands) != 0 {
+		t.Fatalf("should not unmount twice, got %v", comm.Commands)
+	}
+}
+
+func TestStepMountSurrogate_MountErr(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.SurrogateLayout = testSurrogateLayout()
+	config.SurrogateLayout.Prepare()
+	state.Put("surrogate_device", "/dev/sdb")
+	state.Get("communicator").(*commandRecorder).StartExitStatus = 32
+
+	step := new(stepMountSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
+
+func TestStepMountSurrogate_NoLayout(t *testing.T) {
+	state := testState()
+
+	step := new(stepMountSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if comm := state.Get("communicator").(*commandRecorder); len(comm.Commands) != 0 {
+		t.Fatalf("should not run any command, got %v", comm.Commands)
+	}
+}
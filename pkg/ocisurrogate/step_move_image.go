@@ -0,0 +1,53 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepMoveImage moves the image to image_move_to_compartment once it is
+// exported and copied.
+//
+// Produces:
+//
+//	image_compartment_id string - Compartment the image was moved to
+type stepMoveImage struct{}
+
+func (s *stepMoveImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver      = state.Get("driver").(Driver)
+		ui          = state.Get("ui").(packer.Ui)
+		config      = state.Get("config").(*Config)
+		image       = state.Get("image").(core.Image)
+		compartment = config.ImageMoveToCompartment
+	)
+
+	if compartment == "" {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Moving image to compartment %s...", compartment))
+
+	if err := driver.ChangeImageCompartment(ctx, *image.Id, compartment); err != nil {
+		err = fmt.Errorf("Error moving image: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	image.CompartmentId = &compartment
+	state.Put("image", image)
+	state.Put("image_compartment_id", compartment)
+
+	ui.Say("Image moved.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepMoveImage) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
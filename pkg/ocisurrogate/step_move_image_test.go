@@ -0,0 +1,76 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepMoveImage(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageMoveToCompartment = "ocid1.compartment.golden"
+
+	step := new(stepMoveImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	driver := state.Get("driver").(*driverMock)
+	if driver.ChangeImageCompartmentID != "ocid1.compartment.golden" {
+		t.Fatalf("bad compartment: %s", driver.ChangeImageCompartmentID)
+	}
+	if compartment := state.Get("image_compartment_id"); compartment != "ocid1.compartment.golden" {
+		t.Fatalf("bad image_compartment_id: %v", compartment)
+	}
+	if image := state.Get("image").(core.Image); *image.CompartmentId != "ocid1.compartment.golden" {
+		t.Fatalf("should update the image compartment, got %s", *image.CompartmentId)
+	}
+}
+
+func TestStepMoveImage_Err(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.ImageMoveToCompartment = "ocid1.compartment.golden"
+
+	step := new(stepMoveImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.ChangeImageCompartmentErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+	if _, ok := state.GetOk("image_compartment_id"); ok {
+		t.Fatalf("should NOT have image_compartment_id")
+	}
+}
+
+func TestStepMoveImage_Unset(t *testing.T) {
+	state := testState()
+	id := "ocid1.image"
+	state.Put("image", core.Image{Id: &id})
+
+	step := new(stepMoveImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if driver := state.Get("driver").(*driverMock); driver.ChangeImageCompartmentID != "" {
+		t.Fatalf("should not move the image")
+	}
+}
@@ -0,0 +1,58 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepPartitionSurrogate partitions the surrogate volume attached to the
+// builder instance and creates the filesystems described by
+// surrogate_layout.
+type stepPartitionSurrogate struct{}
+
+func (s *stepPartitionSurrogate) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		layout = &config.SurrogateLayout
+		device = surrogateDevice(state)
+	)
+
+	if len(layout.Partitions) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Partitioning surrogate volume %s...", device))
+	commands := []string{
+		fmt.Sprintf("sgdisk --zap-all %s", device),
+		layout.sgdiskCommand(device),
+		fmt.Sprintf("partprobe %s", device),
+		"udevadm settle",
+	}
+	for i := range layout.Partitions {
+		if mkfs := layout.Partitions[i].mkfsCommand(device, i+1); mkfs != "" {
+			commands = append(commands, mkfs)
+		}
+	}
+
+	for _, command := range commands {
+		ui.Message(command)
+		if _, err := runRemoteCommand(ctx, state, command); err != nil {
+			err = fmt.Errorf("Error partitioning surrogate volume: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	ui.Say("Surrogate volume prepared.")
+
+	return multistep.ActionContinue
+}
+
+func (s *stepPartitionSurrogate) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
@@ -0,0 +1,71 @@
+package ocisurrogate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepPartitionSurrogate(t *testing.T) {
+	state := testState()
+	state.Put("surrogate_device", "/dev/oracleoci/oraclevdb")
+	config := state.Get("config").(*Config)
+	config.SurrogateLayout = testSurrogateLayout()
+	config.SurrogateLayout.Prepare()
+
+	step := new(stepPartitionSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	if expected := "sgdisk --zap-all /dev/oracleoci/oraclevdb"; comm.Commands[0] != expected {
+		t.Errorf("bad zap command:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+	for _, command := range comm.Commands {
+		if strings.Contains(command, "/dev/sdb") {
+			t.Errorf("should use the attached device, got %s", command)
+		}
+	}
+}
+
+func TestStepPartitionSurrogate_LayoutDevice(t *testing.T) {
+	state := testState()
+	state.Put("surrogate_device", "/dev/oracleoci/oraclevdb")
+	config := state.Get("config").(*Config)
+	config.SurrogateLayout = testSurrogateLayout()
+	config.SurrogateLayout.Device = "/dev/sdc"
+	config.SurrogateLayout.Prepare()
+
+	step := new(stepPartitionSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	comm := state.Get("communicator").(*commandRecorder)
+	if expected := "sgdisk --zap-all /dev/sdc"; comm.Commands[0] != expected {
+		t.Errorf("bad zap command:\n got: %s\nwant: %s", comm.Commands[0], expected)
+	}
+}
+
+func TestStepPartitionSurrogate_NoLayout(t *testing.T) {
+	state := testState()
+	state.Put("surrogate_device", "/dev/sdb")
+
+	step := new(stepPartitionSurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if comm := state.Get("communicator").(*commandRecorder); len(comm.Commands) != 0 {
+		t.Fatalf("should not run any command, got %v", comm.Commands)
+	}
+}
@@ -0,0 +1,62 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepPruneImages deletes the images of older builds expired by retention
+// once the build succeeds. Failing to prune does not fail the build, which
+// would delete the new image.
+type stepPruneImages struct{}
+
+func (s *stepPruneImages) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver    = state.Get("driver").(Driver)
+		ui        = state.Get("ui").(packer.Ui)
+		config    = state.Get("config").(*Config)
+		image     = state.Get("image").(core.Image)
+		retention = &config.Retention
+	)
+
+	if !retention.isSet() {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Pruning older images in compartment %s...", retention.CompartmentID))
+
+	images, err := driver.ListImages(ctx, retention.filter())
+	if err != nil {
+		ui.Error(fmt.Sprintf("Error listing images to prune: %s", err))
+		return multistep.ActionContinue
+	}
+
+	expired := retention.expired(images, image, time.Now())
+	if len(expired) == 0 {
+		ui.Message("No image to prune.")
+		return multistep.ActionContinue
+	}
+
+	for _, image := range expired {
+		if retention.DryRun {
+			ui.Message(fmt.Sprintf("Would delete image %s (%s).", *image.DisplayName, *image.Id))
+			continue
+		}
+
+		ui.Message(fmt.Sprintf("Deleting image %s (%s)...", *image.DisplayName, *image.Id))
+		if err := driver.DeleteImage(ctx, *image.Id); err != nil {
+			ui.Error(fmt.Sprintf("Error deleting image %s: %s", *image.Id, err))
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepPruneImages) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
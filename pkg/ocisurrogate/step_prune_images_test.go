@@ -0,0 +1,97 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepPruneImages(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.Retention = ImageRetention{NamePrefix: "nightly-", KeepLast: 3, CompartmentID: "ocid1.compartment"}
+
+	step := new(stepPruneImages)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testRetentionImages(time.Now())
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if expected := []string{"ocid1.image.2", "ocid1.image.1"}; !reflect.DeepEqual(driver.DeletedImageIDs, expected) {
+		t.Fatalf("bad deleted images: %v, expected %v", driver.DeletedImageIDs, expected)
+	}
+}
+
+func TestStepPruneImages_DryRun(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.Retention = ImageRetention{NamePrefix: "nightly-", KeepLast: 3, DryRun: true}
+
+	step := new(stepPruneImages)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testRetentionImages(time.Now())
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if len(driver.DeletedImageIDs) != 0 {
+		t.Fatalf("should not delete images in dry run, deleted %v", driver.DeletedImageIDs)
+	}
+}
+
+func TestStepPruneImages_DeleteImageErr(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+	config := state.Get("config").(*Config)
+	config.Retention = ImageRetention{NamePrefix: "nightly-", KeepLast: 3}
+
+	step := new(stepPruneImages)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testRetentionImages(time.Now())
+	driver.DeleteImageErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("pruning should not fail the build, got %#v", action)
+	}
+	if _, ok := state.GetOk("error"); ok {
+		t.Fatalf("should NOT have error")
+	}
+}
+
+func TestStepPruneImages_Unset(t *testing.T) {
+	state := testState()
+	id := "ocid1.image.new"
+	state.Put("image", core.Image{Id: &id})
+
+	step := new(stepPruneImages)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = testRetentionImages(time.Now())
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if len(driver.DeletedImageIDs) != 0 {
+		t.Fatalf("should not delete images, deleted %v", driver.DeletedImageIDs)
+	}
+}
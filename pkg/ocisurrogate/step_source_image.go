@@ -0,0 +1,65 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/builder"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// stepSourceImage resolves the base image of the builder instance from
+// base_image_ocid, base_image_name or base_image_filter, and sets:
+//
+//	source_image core.Image - The base image
+type stepSourceImage struct{}
+
+func (s *stepSourceImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+	)
+
+	ui.Say("Resolving base image...")
+
+	var (
+		image core.Image
+		err   error
+	)
+	if config.BaseImageID != "" {
+		image, err = driver.GetImage(ctx, config.BaseImageID)
+	} else {
+		var images []core.Image
+		images, err = driver.ListImages(ctx, &config.BaseImageFilter)
+		if err == nil {
+			image, err = config.BaseImageFilter.selectImage(images)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("Error resolving base image: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	var name string
+	if image.DisplayName != nil {
+		name = *image.DisplayName
+	}
+	ui.Say(fmt.Sprintf("Using base image '%s' (%s).", name, *image.Id))
+
+	state.Put("source_image", image)
+
+	generatedData := &builder.GeneratedData{State: state}
+	generatedData.Put("SourceImageOCID", *image.Id)
+	generatedData.Put("SourceImageName", name)
+
+	return multistep.ActionContinue
+}
+
+func (s *stepSourceImage) Cleanup(state multistep.StateBag) {
+	// no cleanup
+}
@@ -0,0 +1,90 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestStepSourceImage(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+
+	step := new(stepSourceImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	image := state.Get("source_image").(core.Image)
+	if *image.Id != config.BaseImageID {
+		t.Fatalf("bad source image: %s", *image.Id)
+	}
+	generatedData := state.Get("generated_data").(map[string]interface{})
+	if generatedData["SourceImageOCID"] != config.BaseImageID {
+		t.Fatalf("bad SourceImageOCID: %v", generatedData["SourceImageOCID"])
+	}
+}
+
+func TestStepSourceImage_Filter(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.BaseImageID = ""
+	config.BaseImageFilter = ImageFilter{OperatingSystem: "Oracle Linux", MostRecent: true}
+
+	step := new(stepSourceImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.Images = []core.Image{
+		testImage("ocid1.image.old", "old", time.Now().Add(-time.Hour)),
+		testImage("ocid1.image.new", "new", time.Now()),
+	}
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if image := state.Get("source_image").(core.Image); *image.Id != "ocid1.image.new" {
+		t.Fatalf("bad source image: %s", *image.Id)
+	}
+}
+
+func TestStepSourceImage_NoMatch(t *testing.T) {
+	state := testState()
+	config := state.Get("config").(*Config)
+	config.BaseImageID = ""
+	config.BaseImageFilter = ImageFilter{DisplayName: "missing"}
+
+	step := new(stepSourceImage)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
+
+func TestStepSourceImage_GetImageErr(t *testing.T) {
+	state := testState()
+
+	step := new(stepSourceImage)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.GetImageErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
@@ -2,12 +2,34 @@ package ocisurrogate
 
 import (
 	"bytes"
+	"context"
+	"io/ioutil"
 	"os"
+	"strings"
+	"testing"
 
+	"github.com/hashicorp/packer/common"
 	"github.com/hashicorp/packer/helper/multistep"
 	"github.com/hashicorp/packer/packer"
+	"github.com/oracle/oci-go-sdk/v65/core"
 )
 
+// TestMain runs the tests from a temporary directory, since steps write
+// files such as console histories to the working directory.
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "packer-ocisurrogate")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // TODO(apryde): It would be good not to have to write a key file to disk to
 // load the config.
 func baseTestConfig() *Config {
@@ -59,5 +81,36 @@ func testState() multistep.StateBag {
 		Reader: new(bytes.Buffer),
 		Writer: new(bytes.Buffer),
 	})
+	state.Put("communicator", new(commandRecorder))
+	state.Put("source_image", core.Image{Id: &baseTestConfig.BaseImageID})
+	state.Put("wrappedCommand", common.CommandWrapper(func(command string) (string, error) {
+		return command, nil
+	}))
 	return state
 }
+
+// commandRecorder is a mock communicator that records every command it is
+// asked to run, with the /bin/sh -c wrapping of runRemoteCommand removed.
+type commandRecorder struct {
+	packer.MockCommunicator
+	Commands []string
+}
+
+func (c *commandRecorder) Start(ctx context.Context, cmd *packer.RemoteCmd) error {
+	command := cmd.Command
+	if strings.HasPrefix(command, "/bin/sh -c '") {
+		command = strings.TrimSuffix(strings.TrimPrefix(command, "/bin/sh -c '"), "'")
+		command = strings.Replace(command, `'"'"'`, "'", -1)
+	}
+	c.Commands = append(c.Commands, command)
+	return c.MockCommunicator.Start(ctx, cmd)
+}
+
+// NewConfig prepares a Config from the given raw template values.
+func NewConfig(raws ...interface{}) (*Config, error) {
+	c := new(Config)
+	if err := c.Prepare(raws...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
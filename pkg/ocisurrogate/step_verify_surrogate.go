@@ -0,0 +1,92 @@
+package ocisurrogate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/packer/helper/communicator"
+	"github.com/hashicorp/packer/helper/multistep"
+	"github.com/hashicorp/packer/packer"
+)
+
+// stepVerifySurrogate connects to the surrogate instance and runs the
+// verify_surrogate provisioners on it. The communicator of the builder
+// instance is kept in the state bag.
+//
+// Produces:
+//
+//	surrogate_ip  string - IP of the surrogate instance
+type stepVerifySurrogate struct {
+	connect *communicator.StepConnect
+}
+
+func (s *stepVerifySurrogate) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	var (
+		driver = state.Get("driver").(Driver)
+		ui     = state.Get("ui").(packer.Ui)
+		config = state.Get("config").(*Config)
+		id     = state.Get("instance_surrogate_id").(string)
+		verify = &config.VerifySurrogate
+	)
+
+	if !verify.enabled {
+		return multistep.ActionContinue
+	}
+
+	ip, err := driver.GetInstanceIP(ctx, id)
+	if err != nil {
+		err = fmt.Errorf("Error getting surrogate instance's IP: %s", err)
+		ui.Error(err.Error())
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+	state.Put("surrogate_ip", ip)
+
+	ui.Say(fmt.Sprintf("Verifying surrogate instance at %s...", ip))
+
+	comm := config.Comm
+	comm.SSHTimeout = verify.Timeout
+	comm.WinRMTimeout = verify.Timeout
+	s.connect = &communicator.StepConnect{
+		Config:    &comm,
+		Host:      surrogateHost,
+		SSHConfig: comm.SSHConfigFunc(),
+	}
+
+	builderComm := state.Get("communicator")
+	action := s.connect.Run(ctx, state)
+	surrogateComm, _ := state.GetOk("communicator")
+	state.Put("communicator", builderComm)
+	if action != multistep.ActionContinue {
+		if rawErr, ok := state.GetOk("error"); ok {
+			state.Put("error", fmt.Errorf("Surrogate instance did not become reachable: %s", rawErr))
+		}
+		return action
+	}
+
+	for i, provisioner := range verify.provisioners {
+		ui.Message(fmt.Sprintf("Running verification provisioner %d...", i))
+		if err := provisioner.Provision(ctx, ui, surrogateComm.(packer.Communicator), nil); err != nil {
+			err = fmt.Errorf("Surrogate verification failed: %s", err)
+			ui.Error(err.Error())
+			state.Put("error", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	ui.Say("Surrogate instance verified.")
+
+	return multistep.ActionContinue
+}
+
+// surrogateHost returns the IP of the surrogate instance. ssh_host and
+// winrm_host point to the builder instance, so they are never used here.
+func surrogateHost(state multistep.StateBag) (string, error) {
+	return state.Get("surrogate_ip").(string), nil
+}
+
+func (s *stepVerifySurrogate) Cleanup(state multistep.StateBag) {
+	if s.connect != nil {
+		s.connect.Cleanup(state)
+	}
+}
@@ -0,0 +1,57 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/packer/helper/multistep"
+)
+
+func TestStepVerifySurrogate_Disabled(t *testing.T) {
+	state := testState()
+	state.Put("instance_surrogate_id", "ocid1...")
+
+	step := new(stepVerifySurrogate)
+	defer step.Cleanup(state)
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionContinue {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("surrogate_ip"); ok {
+		t.Fatalf("should NOT have surrogate_ip")
+	}
+}
+
+func TestSurrogateHost(t *testing.T) {
+	state := testState()
+	state.Put("surrogate_ip", "10.0.0.2")
+	config := state.Get("config").(*Config)
+	config.Comm.SSHHost = "10.0.0.1"
+
+	if host, err := surrogateHost(state); err != nil || host != "10.0.0.2" {
+		t.Fatalf("expected the surrogate IP, got %q (%v)", host, err)
+	}
+}
+
+func TestStepVerifySurrogate_GetInstanceIPErr(t *testing.T) {
+	state := testState()
+	state.Put("instance_surrogate_id", "ocid1...")
+	config := state.Get("config").(*Config)
+	config.VerifySurrogate.enabled = true
+
+	step := new(stepVerifySurrogate)
+	defer step.Cleanup(state)
+
+	driver := state.Get("driver").(*driverMock)
+	driver.GetInstanceIPErr = errors.New("error")
+
+	if action := step.Run(context.Background(), state); action != multistep.ActionHalt {
+		t.Fatalf("bad action: %#v", action)
+	}
+
+	if _, ok := state.GetOk("error"); !ok {
+		t.Fatalf("should have error")
+	}
+}
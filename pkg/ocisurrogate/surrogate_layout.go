@@ -0,0 +1,200 @@
+//go:generate mapstructure-to-hcl2 -type SurrogateLayout,SurrogatePartition
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SurrogateLayout describes how the surrogate volume attached to the builder
+// instance is partitioned, formatted and mounted before provisioning.
+type SurrogateLayout struct {
+	// Device is the path of the surrogate volume on the builder instance.
+	// Defaults to the device the surrogate volume is attached as.
+	Device string `mapstructure:"device"`
+	// Partitions are created on the device in the order they are listed.
+	Partitions []SurrogatePartition `mapstructure:"partition"`
+}
+
+// SurrogatePartition is a single GPT partition of the surrogate layout.
+type SurrogatePartition struct {
+	// Type is the GPT partition type code as understood by sgdisk, e.g.
+	// EF00 (EFI system), EF02 (BIOS boot), 8300 (Linux) or BF01 (ZFS).
+	Type string `mapstructure:"type"`
+	// Size of the partition using the sgdisk K, M, G or T suffixes. Leave
+	// empty on the last partition to use the remaining space.
+	Size string `mapstructure:"size"`
+	// Filesystem created on the partition, one of vfat, ext4, xfs or swap.
+	// Use zfs for the vdev of the zfs_root pool, or leave empty to only
+	// create the partition.
+	Filesystem string `mapstructure:"filesystem"`
+	// Label is used as the GPT partition name and the filesystem label.
+	Label string `mapstructure:"label"`
+	// MountPoint is where the filesystem is mounted, relative to the root of
+	// the surrogate, e.g. "/", "/boot" or "/boot/efi".
+	MountPoint string `mapstructure:"mount_point"`
+}
+
+var partitionSizeRe = regexp.MustCompile(`^[0-9]+[KMGT]?$`)
+
+// mkfsCommands maps the supported filesystems to the command creating them
+// and the flag setting their label.
+var mkfsCommands = map[string][2]string{
+	"vfat": {"mkfs.vfat -F 32", "-n"},
+	"ext4": {"mkfs.ext4 -F", "-L"},
+	"xfs":  {"mkfs.xfs -f", "-L"},
+	"swap": {"mkswap", "-L"},
+}
+
+func (l *SurrogateLayout) Prepare() []error {
+	var errs []error
+
+	if len(l.Partitions) == 0 {
+		return errs
+	}
+
+	if l.Device != "" && !path.IsAbs(l.Device) {
+		errs = append(errs, fmt.Errorf("surrogate_layout: device must be an absolute path, got %q", l.Device))
+	}
+
+	mountPoints := map[string]bool{}
+	for i, p := range l.Partitions {
+		n := i + 1
+		if p.Type == "" {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: 'type' must be specified", n))
+		}
+		if p.Size == "" && n != len(l.Partitions) {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: 'size' can only be omitted on the last partition", n))
+		}
+		if p.Size != "" && !partitionSizeRe.MatchString(p.Size) {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: invalid size %q", n, p.Size))
+		}
+		if p.Filesystem != "" && p.Filesystem != "zfs" {
+			if _, ok := mkfsCommands[p.Filesystem]; !ok {
+				errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: unsupported filesystem %q", n, p.Filesystem))
+			}
+		}
+		if len(p.Label) > 36 {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: label can be at most 36 characters", n))
+		}
+		if p.MountPoint == "" {
+			continue
+		}
+		if p.Filesystem == "" || p.Filesystem == "swap" || p.Filesystem == "zfs" {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: mount_point requires a mountable filesystem", n))
+		}
+		if !path.IsAbs(p.MountPoint) {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: mount_point must be an absolute path, got %q", n, p.MountPoint))
+		}
+		mountPoint := path.Clean(p.MountPoint)
+		if mountPoints[mountPoint] {
+			errs = append(errs, fmt.Errorf("surrogate_layout: partition %d: mount_point %q is used more than once", n, p.MountPoint))
+		}
+		mountPoints[mountPoint] = true
+		l.Partitions[i].MountPoint = mountPoint
+	}
+
+	return errs
+}
+
+// hasRootMount reports whether a partition of the layout is mounted on /.
+func (l *SurrogateLayout) hasRootMount() bool {
+	for _, p := range l.Partitions {
+		if p.MountPoint == "/" {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionNumber returns the number of the first partition of the layout
+// with the given filesystem, or 0 if there is none.
+func (l *SurrogateLayout) partitionNumber(filesystem string) int {
+	for i, p := range l.Partitions {
+		if p.Filesystem == filesystem {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// partitionDevice returns the device node of partition number n of device.
+// Devices whose name ends in a digit, such as NVMe namespaces, use a "p"
+// separator.
+func partitionDevice(device string, n int) string {
+	if last := device[len(device)-1]; last >= '0' && last <= '9' {
+		return fmt.Sprintf("%sp%d", device, n)
+	}
+	return fmt.Sprintf("%s%d", device, n)
+}
+
+// sgdiskCommand returns the command that creates the partitions of the layout
+// on device.
+func (l *SurrogateLayout) sgdiskCommand(device string) string {
+	args := []string{"sgdisk"}
+	for i, p := range l.Partitions {
+		n := i + 1
+		end := "0"
+		if p.Size != "" {
+			end = "+" + p.Size
+		}
+		args = append(args, fmt.Sprintf("-n%d:0:%s", n, end), fmt.Sprintf("-t%d:%s", n, p.Type))
+		if p.Label != "" {
+			args = append(args, shellQuote(fmt.Sprintf("-c%d:%s", n, p.Label)))
+		}
+	}
+	args = append(args, device)
+	return strings.Join(args, " ")
+}
+
+// mkfsCommand returns the command that creates the filesystem of partition
+// number n on device, or an empty string if it has no filesystem.
+func (p *SurrogatePartition) mkfsCommand(device string, n int) string {
+	mkfs, ok := mkfsCommands[p.Filesystem]
+	if !ok {
+		return ""
+	}
+	args := []string{mkfs[0]}
+	if p.Label != "" {
+		args = append(args, mkfs[1], shellQuote(p.Label))
+	}
+	args = append(args, partitionDevice(device, n))
+	return strings.Join(args, " ")
+}
+
+// surrogateMount is a filesystem of the surrogate layout to be mounted.
+type surrogateMount struct {
+	Device     string
+	MountPoint string
+}
+
+// mounts returns the partitions of the layout that have a mount point, in
+// the order they have to be mounted.
+func (l *SurrogateLayout) mounts(device string) []surrogateMount {
+	var mounts []surrogateMount
+	for i, p := range l.Partitions {
+		if p.MountPoint == "" {
+			continue
+		}
+		mounts = append(mounts, surrogateMount{
+			Device:     partitionDevice(device, i+1),
+			MountPoint: p.MountPoint,
+		})
+	}
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return mountDepth(mounts[i].MountPoint) < mountDepth(mounts[j].MountPoint)
+	})
+	return mounts
+}
+
+// mountDepth returns the number of path elements of mountPoint, "/" being 0.
+func mountDepth(mountPoint string) int {
+	if mountPoint == "/" {
+		return 0
+	}
+	return strings.Count(mountPoint, "/")
+}
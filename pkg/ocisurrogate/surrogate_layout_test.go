@@ -0,0 +1,87 @@
+package ocisurrogate
+
+import (
+	"testing"
+)
+
+func testSurrogateLayout() SurrogateLayout {
+	return SurrogateLayout{
+		Partitions: []SurrogatePartition{
+			{Type: "EF00", Size: "210M", Filesystem: "vfat", Label: "EFI", MountPoint: "/boot/efi"},
+			{Type: "8300", Size: "1G", Filesystem: "ext4", Label: "boot", MountPoint: "/boot"},
+			{Type: "8300", Filesystem: "xfs", Label: "root", MountPoint: "/"},
+		},
+	}
+}
+
+func TestSurrogateLayoutPrepare(t *testing.T) {
+	l := testSurrogateLayout()
+	if errs := l.Prepare(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if l.Device != "" {
+		t.Errorf("expected the device to be resolved at attach time, got %s", l.Device)
+	}
+
+	empty := SurrogateLayout{}
+	if errs := empty.Prepare(); len(errs) != 0 {
+		t.Fatalf("unexpected errors for an empty layout: %v", errs)
+	}
+	if empty.Device != "" {
+		t.Errorf("expected no default device for an empty layout, got %s", empty.Device)
+	}
+}
+
+func TestSurrogateLayoutPrepare_Errors(t *testing.T) {
+	cases := map[string]func(l *SurrogateLayout){
+		"missing type":        func(l *SurrogateLayout) { l.Partitions[0].Type = "" },
+		"size not last":       func(l *SurrogateLayout) { l.Partitions[0].Size = "" },
+		"bad size":            func(l *SurrogateLayout) { l.Partitions[1].Size = "1GB" },
+		"bad filesystem":      func(l *SurrogateLayout) { l.Partitions[1].Filesystem = "ntfs" },
+		"relative mount":      func(l *SurrogateLayout) { l.Partitions[1].MountPoint = "boot" },
+		"duplicate mount":     func(l *SurrogateLayout) { l.Partitions[1].MountPoint = "/boot/efi/" },
+		"mount without fs":    func(l *SurrogateLayout) { l.Partitions[1].Filesystem = "" },
+		"mount swap":          func(l *SurrogateLayout) { l.Partitions[1].Filesystem = "swap" },
+		"relative device":     func(l *SurrogateLayout) { l.Device = "sdb" },
+		"label over 36 chars": func(l *SurrogateLayout) { l.Partitions[2].Label = "0123456789012345678901234567890123456" },
+	}
+
+	for name, modify := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := testSurrogateLayout()
+			modify(&l)
+			if errs := l.Prepare(); len(errs) == 0 {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestSurrogateLayoutCommands(t *testing.T) {
+	l := testSurrogateLayout()
+	l.Prepare()
+
+	expected := "sgdisk -n1:0:+210M -t1:EF00 '-c1:EFI' -n2:0:+1G -t2:8300 '-c2:boot' -n3:0:0 -t3:8300 '-c3:root' /dev/sdb"
+	if got := l.sgdiskCommand("/dev/sdb"); got != expected {
+		t.Errorf("bad sgdisk command:\n got: %s\nwant: %s", got, expected)
+	}
+
+	expected = "mkfs.ext4 -F -L 'boot' /dev/nvme1n1p2"
+	if got := l.Partitions[1].mkfsCommand("/dev/nvme1n1", 2); got != expected {
+		t.Errorf("bad mkfs command:\n got: %s\nwant: %s", got, expected)
+	}
+
+	mounts := l.mounts("/dev/sdb")
+	order := []string{"/", "/boot", "/boot/efi"}
+	if len(mounts) != len(order) {
+		t.Fatalf("expected %d mounts, got %d", len(order), len(mounts))
+	}
+	for i, m := range mounts {
+		if m.MountPoint != order[i] {
+			t.Errorf("mount %d: expected %s, got %s", i, order[i], m.MountPoint)
+		}
+	}
+	if mounts[0].Device != "/dev/sdb3" {
+		t.Errorf("expected / on /dev/sdb3, got %s", mounts[0].Device)
+	}
+}
@@ -0,0 +1,69 @@
+//go:generate mapstructure-to-hcl2 -type SurrogateVerification,VerifyProvisioner
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/packer/provisioner/shell"
+	"github.com/hashicorp/packer/template/interpolate"
+)
+
+// SurrogateVerification checks that the surrogate instance boots before its
+// image is created: the builder connects to it with the communicator
+// settings of the builder instance and runs the validation provisioners.
+// The verification runs when a timeout or a provisioner is set.
+type SurrogateVerification struct {
+	// Timeout is how long to wait for the surrogate instance to become
+	// reachable. Defaults to 10m.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Provisioners are shell provisioners run on the surrogate instance. The
+	// build fails when one of them fails.
+	Provisioners []VerifyProvisioner `mapstructure:"provisioner"`
+
+	enabled      bool
+	provisioners []*shell.Provisioner
+}
+
+// VerifyProvisioner takes a subset of the options of the shell provisioner.
+type VerifyProvisioner struct {
+	Inline          []string `mapstructure:"inline"`
+	Scripts         []string `mapstructure:"scripts"`
+	EnvironmentVars []string `mapstructure:"environment_vars"`
+	ExecuteCommand  string   `mapstructure:"execute_command"`
+}
+
+// Prepare validates the verification. Interpolation of the provisioners is
+// left to the shell provisioner, with the user variables of the build.
+func (v *SurrogateVerification) Prepare(userVars map[string]string, ctx *interpolate.Context) []error {
+	var errs []error
+
+	v.enabled = v.Timeout != 0 || len(v.Provisioners) > 0
+	if !v.enabled {
+		return errs
+	}
+
+	if v.Timeout == 0 {
+		v.Timeout = 10 * time.Minute
+	}
+
+	v.provisioners = nil
+	for i, p := range v.Provisioners {
+		provisioner := new(shell.Provisioner)
+		err := provisioner.Prepare(map[string]interface{}{
+			"packer_user_variables": userVars,
+			"inline":                p.Inline,
+			"scripts":               p.Scripts,
+			"environment_vars":      p.EnvironmentVars,
+			"execute_command":       p.ExecuteCommand,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("verify_surrogate: provisioner %d: %s", i, err))
+			continue
+		}
+		v.provisioners = append(v.provisioners, provisioner)
+	}
+
+	return errs
+}
@@ -0,0 +1,47 @@
+package ocisurrogate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSurrogateVerificationPrepare(t *testing.T) {
+	v := SurrogateVerification{
+		Provisioners: []VerifyProvisioner{
+			{Inline: []string{"zpool status -x rpool"}},
+		},
+	}
+	if errs := v.Prepare(nil, nil); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !v.enabled {
+		t.Fatalf("should be enabled by the provisioner")
+	}
+	if v.Timeout != 10*time.Minute {
+		t.Errorf("bad timeout default: %s", v.Timeout)
+	}
+	if len(v.provisioners) != 1 {
+		t.Errorf("expected 1 prepared provisioner, got %d", len(v.provisioners))
+	}
+}
+
+func TestSurrogateVerificationPrepare_Disabled(t *testing.T) {
+	v := SurrogateVerification{}
+	if errs := v.Prepare(nil, nil); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if v.enabled {
+		t.Fatalf("should not be enabled")
+	}
+}
+
+func TestSurrogateVerificationPrepare_BadProvisioner(t *testing.T) {
+	v := SurrogateVerification{
+		Provisioners: []VerifyProvisioner{
+			{Scripts: []string{"/nonexistent/verify.sh"}},
+		},
+	}
+	if errs := v.Prepare(nil, nil); len(errs) != 1 {
+		t.Fatalf("expected an error for the missing script, got %v", errs)
+	}
+}
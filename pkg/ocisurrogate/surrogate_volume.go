@@ -0,0 +1,73 @@
+//go:generate mapstructure-to-hcl2 -type SurrogateVolume
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SurrogateVolume is a block volume created for the build, attached to the
+// builder instance alongside the boot clone and then to the surrogate
+// instance when it is launched, so that they are there on its first boot.
+// The image is captured from the boot volume only: the volumes are not part
+// of it and are deleted at the end of the build. Any filesystem mounted from
+// them on the builder instance must be unmounted by the provisioners.
+type SurrogateVolume struct {
+	// Name is the display name of the volume. Defaults to
+	// <image_name>-volume-<index>.
+	Name string `mapstructure:"name"`
+	// SizeInGBs is the size of the volume. Defaults to 50, or to the size
+	// of the source.
+	SizeInGBs int64 `mapstructure:"size_in_gbs"`
+	// VpusPerGB is the performance of the volume: 0 (lower cost), 10
+	// (balanced, the default) or 20 (higher performance).
+	VpusPerGB *int64 `mapstructure:"vpus_per_gb"`
+	// Device is the consistent device path the volume is attached at, e.g.
+	// /dev/oracleoci/oraclevdc, on both instances.
+	Device string `mapstructure:"device"`
+	// Source is the OCID of a volume or volume backup the volume is created
+	// from. The volume is empty when unset.
+	Source string `mapstructure:"source"`
+}
+
+func (v *SurrogateVolume) Prepare(index int, imageName string) []error {
+	var errs []error
+
+	if v.Name == "" {
+		v.Name = fmt.Sprintf("%s-volume-%d", imageName, index)
+	}
+	if v.SizeInGBs == 0 && v.Source == "" {
+		v.SizeInGBs = 50
+	}
+	if v.SizeInGBs != 0 && v.SizeInGBs < 50 {
+		errs = append(errs, fmt.Errorf("surrogate_volumes[%d]: size_in_gbs must be at least 50, got %d", index, v.SizeInGBs))
+	}
+	if v.VpusPerGB != nil {
+		switch *v.VpusPerGB {
+		case 0, 10, 20:
+		default:
+			errs = append(errs, fmt.Errorf("surrogate_volumes[%d]: vpus_per_gb must be one of 0, 10 or 20, got %d", index, *v.VpusPerGB))
+		}
+	}
+	if v.Device != "" && !strings.HasPrefix(v.Device, "/dev/oracleoci/") {
+		errs = append(errs, fmt.Errorf("surrogate_volumes[%d]: device must be a /dev/oracleoci/ path, got %q", index, v.Device))
+	}
+	if v.Source != "" && v.sourceType() == "" {
+		errs = append(errs, fmt.Errorf("surrogate_volumes[%d]: source must be the OCID of a volume or volume backup, got %q", index, v.Source))
+	}
+
+	return errs
+}
+
+// sourceType returns "volume" or "volumeBackup" depending on the OCID of the
+// source.
+func (v *SurrogateVolume) sourceType() string {
+	switch {
+	case strings.HasPrefix(v.Source, "ocid1.volume."):
+		return "volume"
+	case strings.HasPrefix(v.Source, "ocid1.volumebackup."):
+		return "volumeBackup"
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package ocisurrogate
+
+import (
+	"testing"
+)
+
+func TestSurrogateVolumePrepare(t *testing.T) {
+	v := SurrogateVolume{Device: "/dev/oracleoci/oraclevdc"}
+	if errs := v.Prepare(0, "HelloWorld"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if v.Name != "HelloWorld-volume-0" {
+		t.Errorf("bad name default: %s", v.Name)
+	}
+	if v.SizeInGBs != 50 {
+		t.Errorf("bad size_in_gbs default: %d", v.SizeInGBs)
+	}
+	if v.VpusPerGB != nil {
+		t.Errorf("vpus_per_gb should be left to the service default")
+	}
+}
+
+func TestSurrogateVolumePrepare_Source(t *testing.T) {
+	cases := map[string]string{
+		"ocid1.volume.oc1.iad.aaaa":       "volume",
+		"ocid1.volumebackup.oc1.iad.aaaa": "volumeBackup",
+	}
+	for source, expected := range cases {
+		v := SurrogateVolume{Source: source}
+		if errs := v.Prepare(0, "HelloWorld"); len(errs) != 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if v.SizeInGBs != 0 {
+			t.Errorf("%s: size_in_gbs should default to the size of the source", source)
+		}
+		if v.sourceType() != expected {
+			t.Errorf("%s: expected source type %s, got %s", source, expected, v.sourceType())
+		}
+	}
+}
+
+func TestSurrogateVolumePrepare_Errors(t *testing.T) {
+	vpus := int64(15)
+	cases := []SurrogateVolume{
+		{SizeInGBs: 10},
+		{VpusPerGB: &vpus},
+		{Device: "/dev/sdc"},
+		{Source: "ocid1.image.oc1.iad.aaaa"},
+	}
+	for _, v := range cases {
+		if errs := v.Prepare(0, "HelloWorld"); len(errs) != 1 {
+			t.Errorf("expected an error for %+v, got %v", v, errs)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package ocisurrogate
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/packer/packer"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	ociauth "github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// SweepConfig selects the resources left behind by builds that were killed
+// before cleaning up: the instances, boot volumes and block volumes of a
+// compartment carrying the packer_build_uuid run tag and Tags, created more
+// than OlderThan ago, and their volume attachments.
+type SweepConfig struct {
+	CompartmentID string
+	OlderThan     time.Duration
+	Tags          map[string]string
+	// DryRun only lists the resources.
+	DryRun bool
+}
+
+// sweepResources are the resources to sweep.
+type sweepResources struct {
+	attachments []core.VolumeAttachment
+	instances   []core.Instance
+	bootVolumes []core.BootVolume
+	volumes     []core.Volume
+}
+
+func (r *sweepResources) empty() bool {
+	return len(r.attachments) == 0 && len(r.instances) == 0 && len(r.bootVolumes) == 0 && len(r.volumes) == 0
+}
+
+// matches reports whether a resource with the freeform tags, created at
+// created, is to be swept at now.
+func (c *SweepConfig) matches(tags map[string]string, created *ocicommon.SDKTime, now time.Time) bool {
+	if _, ok := tags[runTagBuildUUID]; !ok {
+		return false
+	}
+	for k, v := range c.Tags {
+		if value, ok := tags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return created != nil && now.Sub(created.Time) > c.OlderThan
+}
+
+// findBootVolumes returns the boot volumes to sweep at now.
+func (c *SweepConfig) findBootVolumes(ctx context.Context, driver Driver, now time.Time) ([]core.BootVolume, error) {
+	bootVolumes, err := driver.ListBootVolumes(ctx, c.CompartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing boot volumes: %s", err)
+	}
+
+	var found []core.BootVolume
+	for _, volume := range bootVolumes {
+		if volume.LifecycleState == core.BootVolumeLifecycleStateTerminating ||
+			volume.LifecycleState == core.BootVolumeLifecycleStateTerminated {
+			continue
+		}
+		if c.matches(volume.FreeformTags, volume.TimeCreated, now) {
+			found = append(found, volume)
+		}
+	}
+	return found, nil
+}
+
+// find returns the resources to sweep at now.
+func (c *SweepConfig) find(ctx context.Context, driver Driver, now time.Time) (*sweepResources, error) {
+	var resources sweepResources
+
+	instances, err := driver.ListInstances(ctx, c.CompartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing instances: %s", err)
+	}
+	swept := map[string]bool{}
+	for _, instance := range instances {
+		if instance.LifecycleState == core.InstanceLifecycleStateTerminating ||
+			instance.LifecycleState == core.InstanceLifecycleStateTerminated {
+			continue
+		}
+		if c.matches(instance.FreeformTags, instance.TimeCreated, now) {
+			resources.instances = append(resources.instances, instance)
+			swept[*instance.Id] = true
+		}
+	}
+
+	resources.bootVolumes, err = c.findBootVolumes(ctx, driver, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range resources.bootVolumes {
+		swept[*volume.Id] = true
+	}
+
+	volumes, err := driver.ListVolumes(ctx, c.CompartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing volumes: %s", err)
+	}
+	for _, volume := range volumes {
+		if volume.LifecycleState == core.VolumeLifecycleStateTerminating ||
+			volume.LifecycleState == core.VolumeLifecycleStateTerminated {
+			continue
+		}
+		if c.matches(volume.FreeformTags, volume.TimeCreated, now) {
+			resources.volumes = append(resources.volumes, volume)
+			swept[*volume.Id] = true
+		}
+	}
+
+	// Volume attachments cannot be tagged, they are swept with the instance
+	// or the volume they attach.
+	attachments, err := driver.ListVolumeAttachments(ctx, c.CompartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing volume attachments: %s", err)
+	}
+	for _, attachment := range attachments {
+		state := attachment.GetLifecycleState()
+		if state != core.VolumeAttachmentLifecycleStateAttaching && state != core.VolumeAttachmentLifecycleStateAttached {
+			continue
+		}
+		if swept[*attachment.GetInstanceId()] || swept[*attachment.GetVolumeId()] {
+			resources.attachments = append(resources.attachments, attachment)
+		}
+	}
+
+	return &resources, nil
+}
+
+// sweep removes the resources to sweep at now, in the order they depend on
+// each other: volume attachments are detached, instances terminated, then
+// the volumes deleted. Boot volumes are looked up again once the instances
+// are terminated, as those an instance boots from are deleted with it.
+func sweep(ctx context.Context, driver Driver, c *SweepConfig, out io.Writer, now time.Time) error {
+	resources, err := c.find(ctx, driver, now)
+	if err != nil {
+		return err
+	}
+	if resources.empty() {
+		fmt.Fprintln(out, "No resource to sweep.")
+		return nil
+	}
+
+	for _, attachment := range resources.attachments {
+		fmt.Fprintf(out, "volume attachment %s: volume %s on instance %s\n",
+			*attachment.GetId(), *attachment.GetVolumeId(), *attachment.GetInstanceId())
+	}
+	for _, instance := range resources.instances {
+		fmt.Fprintf(out, "instance %s: %s, %s, created %s\n",
+			*instance.Id, displayName(instance.DisplayName), instance.LifecycleState, instance.TimeCreated.Format(time.RFC3339))
+	}
+	for _, volume := range resources.bootVolumes {
+		fmt.Fprintf(out, "boot volume %s: %s, created %s\n",
+			*volume.Id, displayName(volume.DisplayName), volume.TimeCreated.Format(time.RFC3339))
+	}
+	for _, volume := range resources.volumes {
+		fmt.Fprintf(out, "volume %s: %s, created %s\n",
+			*volume.Id, displayName(volume.DisplayName), volume.TimeCreated.Format(time.RFC3339))
+	}
+
+	if c.DryRun {
+		return nil
+	}
+
+	var errs *packer.MultiError
+
+	for _, attachment := range resources.attachments {
+		id := *attachment.GetId()
+		fmt.Fprintf(out, "Detaching volume attachment %s...\n", id)
+		if err := driver.DetachVolume(ctx, id); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error detaching volume attachment %s: %s", id, err))
+			continue
+		}
+		if err := driver.WaitForVolumeAttachmentState(ctx, id, []string{"DETACHING"}, "DETACHED"); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error waiting for volume attachment %s to detach: %s", id, err))
+		}
+	}
+
+	for _, instance := range resources.instances {
+		id := *instance.Id
+		fmt.Fprintf(out, "Terminating instance %s...\n", id)
+		if err := driver.TerminateInstance(ctx, id); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error terminating instance %s: %s", id, err))
+			continue
+		}
+		if err := driver.WaitForInstanceState(ctx, id, []string{"TERMINATING"}, "TERMINATED"); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error waiting for instance %s to terminate: %s", id, err))
+		}
+	}
+
+	if len(resources.instances) > 0 {
+		resources.bootVolumes, err = c.findBootVolumes(ctx, driver, now)
+		if err != nil {
+			return packer.MultiErrorAppend(errs, err)
+		}
+	}
+	for _, volume := range resources.bootVolumes {
+		id := *volume.Id
+		fmt.Fprintf(out, "Deleting boot volume %s...\n", id)
+		if err := driver.DeleteBootVolume(ctx, id); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error deleting boot volume %s: %s", id, err))
+		}
+	}
+
+	for _, volume := range resources.volumes {
+		id := *volume.Id
+		fmt.Fprintf(out, "Deleting volume %s...\n", id)
+		if err := driver.DeleteVolume(ctx, id); err != nil {
+			errs = packer.MultiErrorAppend(errs, fmt.Errorf("Error deleting volume %s: %s", id, err))
+		}
+	}
+
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+func displayName(name *string) string {
+	if name == nil {
+		return ""
+	}
+	return *name
+}
+
+// Sweep runs the sweep subcommand with the command line arguments args, and
+// returns its exit status.
+func Sweep(args []string, out io.Writer) int {
+	var (
+		c                  SweepConfig
+		tags               = tagFlag{}
+		accessCfgFile      string
+		profile            string
+		region             string
+		instancePrincipals bool
+	)
+
+	flags := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		fmt.Fprintln(out, "Usage: packer-builder-oracle-ocisurrogate sweep --compartment OCID [options]")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Terminates and deletes the instances, boot volumes and volumes left behind by")
+		fmt.Fprintln(out, "killed builds, which carry the packer_build_uuid run tag.")
+		fmt.Fprintln(out)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&c.CompartmentID, "compartment", "", "OCID of the compartment to sweep")
+	flags.DurationVar(&c.OlderThan, "older-than", 6*time.Hour, "only sweep resources created longer ago than this")
+	flags.Var(tags, "tag", "only sweep resources with this run tag, as key=value; may be repeated")
+	flags.BoolVar(&c.DryRun, "dry-run", false, "only list the resources to sweep")
+	flags.StringVar(&accessCfgFile, "access-cfg-file", "", "OCI configuration file, defaults to ~/.oci/config")
+	flags.StringVar(&profile, "profile", "DEFAULT", "profile of the OCI configuration file")
+	flags.StringVar(&region, "region", "", "region to sweep, defaults to the region of the profile")
+	flags.BoolVar(&instancePrincipals, "instance-principals", false, "authenticate with instance principals")
+
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+	if c.CompartmentID == "" {
+		fmt.Fprintln(out, "--compartment must be specified")
+		flags.Usage()
+		return 2
+	}
+	c.Tags = tags
+
+	configProvider, err := sweepConfigProvider(accessCfgFile, profile, region, instancePrincipals)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	driver, err := NewDriverOCI(&Config{configProvider: configProvider, CompartmentID: c.CompartmentID})
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	if err := sweep(context.Background(), driver, &c, out, time.Now()); err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	return 0
+}
+
+// sweepConfigProvider returns the configuration provider of the sweep
+// subcommand.
+func sweepConfigProvider(accessCfgFile, profile, region string, instancePrincipals bool) (ocicommon.ConfigurationProvider, error) {
+	if instancePrincipals {
+		if region != "" {
+			return nil, errors.New("--region cannot be set with --instance-principals")
+		}
+		return ociauth.InstancePrincipalConfigurationProvider()
+	}
+
+	if accessCfgFile == "" {
+		path, err := getDefaultOCISettingsPath()
+		if err != nil {
+			return nil, fmt.Errorf("Error locating the OCI configuration file: %s", err)
+		}
+		accessCfgFile = path
+	}
+	fileProvider, err := ocicommon.ConfigurationProviderFromFileWithProfile(accessCfgFile, profile, "")
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		return fileProvider, nil
+	}
+	return ocicommon.ComposingConfigurationProvider([]ocicommon.ConfigurationProvider{
+		NewRawConfigurationProvider("", "", region, "", "", nil),
+		fileProvider,
+	})
+}
+
+// tagFlag is a repeated key=value command line flag.
+type tagFlag map[string]string
+
+func (f tagFlag) String() string {
+	var tags []string
+	for k, v := range f {
+		tags = append(tags, k+"="+v)
+	}
+	return strings.Join(tags, ",")
+}
+
+func (f tagFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("tag %q is not key=value", value)
+	}
+	f[parts[0]] = parts[1]
+	return nil
+}
@@ -0,0 +1,176 @@
+package ocisurrogate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func testSweepDriver(now time.Time) *driverMock {
+	old := &common.SDKTime{Time: now.Add(-24 * time.Hour)}
+	recent := &common.SDKTime{Time: now.Add(-time.Hour)}
+	run := map[string]string{runTagBuildUUID: "a1b2", runTagBuildName: "ol8"}
+	other := map[string]string{runTagBuildUUID: "c3d4", runTagBuildName: "ol7"}
+
+	instance := func(id string, tags map[string]string, created *common.SDKTime, state core.InstanceLifecycleStateEnum) core.Instance {
+		return core.Instance{Id: common.String(id), FreeformTags: tags, TimeCreated: created, LifecycleState: state}
+	}
+	attachment := func(id string, instanceID string, volumeID string, state core.VolumeAttachmentLifecycleStateEnum) core.VolumeAttachment {
+		return core.ParavirtualizedVolumeAttachment{
+			Id: common.String(id), InstanceId: common.String(instanceID), VolumeId: common.String(volumeID), LifecycleState: state,
+		}
+	}
+
+	return &driverMock{
+		Instances: []core.Instance{
+			instance("ocid1.instance.builder", run, old, core.InstanceLifecycleStateRunning),
+			instance("ocid1.instance.surrogate", other, old, core.InstanceLifecycleStateStopped),
+			instance("ocid1.instance.running", run, recent, core.InstanceLifecycleStateRunning),
+			instance("ocid1.instance.untagged", nil, old, core.InstanceLifecycleStateRunning),
+			instance("ocid1.instance.terminated", run, old, core.InstanceLifecycleStateTerminated),
+		},
+		BootVolumes: []core.BootVolume{
+			{Id: common.String("ocid1.bootvolume.clone"), FreeformTags: run, TimeCreated: old, LifecycleState: core.BootVolumeLifecycleStateAvailable},
+			{Id: common.String("ocid1.bootvolume.untagged"), TimeCreated: old, LifecycleState: core.BootVolumeLifecycleStateAvailable},
+		},
+		Volumes: []core.Volume{
+			{Id: common.String("ocid1.volume.data"), FreeformTags: run, TimeCreated: old, LifecycleState: core.VolumeLifecycleStateAvailable},
+			{Id: common.String("ocid1.volume.recent"), FreeformTags: run, TimeCreated: recent, LifecycleState: core.VolumeLifecycleStateAvailable},
+		},
+		VolumeAttachments: []core.VolumeAttachment{
+			attachment("ocid1.volumeattachment.clone", "ocid1.instance.builder", "ocid1.bootvolume.clone", core.VolumeAttachmentLifecycleStateAttached),
+			attachment("ocid1.volumeattachment.data", "ocid1.instance.untagged", "ocid1.volume.data", core.VolumeAttachmentLifecycleStateAttached),
+			attachment("ocid1.volumeattachment.detached", "ocid1.instance.builder", "ocid1.volume.old", core.VolumeAttachmentLifecycleStateDetached),
+			attachment("ocid1.volumeattachment.untagged", "ocid1.instance.untagged", "ocid1.bootvolume.untagged", core.VolumeAttachmentLifecycleStateAttached),
+		},
+	}
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Now()
+	driver := testSweepDriver(now)
+	c := &SweepConfig{CompartmentID: "ocid1.compartment", OlderThan: 6 * time.Hour}
+	var out bytes.Buffer
+
+	if err := sweep(context.Background(), driver, c, &out, now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []string{"ocid1.volumeattachment.clone", "ocid1.volumeattachment.data"}; !reflect.DeepEqual(driver.DetachVolumeIDs, expected) {
+		t.Errorf("bad detached attachments: %v", driver.DetachVolumeIDs)
+	}
+	if expected := []string{"ocid1.instance.builder", "ocid1.instance.surrogate"}; !reflect.DeepEqual(driver.TerminatedInstanceIDs, expected) {
+		t.Errorf("bad terminated instances: %v", driver.TerminatedInstanceIDs)
+	}
+	if expected := []string{"ocid1.bootvolume.clone"}; !reflect.DeepEqual(driver.DeletedBootVolumeIDs, expected) {
+		t.Errorf("bad deleted boot volumes: %v", driver.DeletedBootVolumeIDs)
+	}
+	if expected := []string{"ocid1.volume.data"}; !reflect.DeepEqual(driver.DeleteVolumeIDs, expected) {
+		t.Errorf("bad deleted volumes: %v", driver.DeleteVolumeIDs)
+	}
+
+	listed := out.String()
+	detached := strings.Index(listed, "Detaching volume attachment")
+	terminated := strings.Index(listed, "Terminating instance")
+	deleted := strings.Index(listed, "Deleting boot volume")
+	if detached < 0 || terminated < detached || deleted < terminated {
+		t.Errorf("should detach, then terminate, then delete, got:\n%s", listed)
+	}
+}
+
+func TestSweep_DryRun(t *testing.T) {
+	now := time.Now()
+	driver := testSweepDriver(now)
+	c := &SweepConfig{CompartmentID: "ocid1.compartment", OlderThan: 6 * time.Hour, DryRun: true}
+	var out bytes.Buffer
+
+	if err := sweep(context.Background(), driver, c, &out, now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(driver.DetachVolumeIDs) != 0 || len(driver.TerminatedInstanceIDs) != 0 ||
+		len(driver.DeletedBootVolumeIDs) != 0 || len(driver.DeleteVolumeIDs) != 0 {
+		t.Fatalf("should not remove anything in dry run")
+	}
+	for _, id := range []string{"ocid1.volumeattachment.clone", "ocid1.instance.builder", "ocid1.bootvolume.clone", "ocid1.volume.data"} {
+		if !strings.Contains(out.String(), id) {
+			t.Errorf("should list %s, got:\n%s", id, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "ocid1.instance.running") {
+		t.Errorf("should not list recent resources, got:\n%s", out.String())
+	}
+}
+
+func TestSweep_Tags(t *testing.T) {
+	now := time.Now()
+	driver := testSweepDriver(now)
+	c := &SweepConfig{CompartmentID: "ocid1.compartment", OlderThan: 6 * time.Hour, Tags: map[string]string{runTagBuildName: "ol7"}}
+
+	if err := sweep(context.Background(), driver, c, &bytes.Buffer{}, now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []string{"ocid1.instance.surrogate"}; !reflect.DeepEqual(driver.TerminatedInstanceIDs, expected) {
+		t.Errorf("bad terminated instances: %v", driver.TerminatedInstanceIDs)
+	}
+	if len(driver.DetachVolumeIDs) != 0 || len(driver.DeletedBootVolumeIDs) != 0 || len(driver.DeleteVolumeIDs) != 0 {
+		t.Errorf("should only sweep the resources with the tags")
+	}
+}
+
+func TestSweep_Errors(t *testing.T) {
+	now := time.Now()
+	driver := testSweepDriver(now)
+	driver.TerminateInstanceErr = errors.New("error")
+	c := &SweepConfig{CompartmentID: "ocid1.compartment", OlderThan: 6 * time.Hour}
+
+	err := sweep(context.Background(), driver, c, &bytes.Buffer{}, now)
+	if err == nil || !strings.Contains(err.Error(), "ocid1.instance.builder") {
+		t.Fatalf("expected a termination error, got %v", err)
+	}
+	if expected := []string{"ocid1.volume.data"}; !reflect.DeepEqual(driver.DeleteVolumeIDs, expected) {
+		t.Errorf("should keep sweeping after an error, deleted volumes: %v", driver.DeleteVolumeIDs)
+	}
+
+	driver = testSweepDriver(now)
+	driver.ListInstancesErr = errors.New("error")
+	if err := sweep(context.Background(), driver, c, &bytes.Buffer{}, now); err == nil {
+		t.Fatalf("expected a listing error")
+	}
+	if len(driver.DetachVolumeIDs) != 0 {
+		t.Errorf("should not sweep anything when listing fails")
+	}
+}
+
+func TestSweepCommandLine(t *testing.T) {
+	var out bytes.Buffer
+	if status := Sweep([]string{"--older-than", "6h"}, &out); status != 2 {
+		t.Errorf("expected status 2 without a compartment, got %d", status)
+	}
+	if !strings.Contains(out.String(), "--compartment must be specified") {
+		t.Errorf("bad output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if status := Sweep([]string{"--compartment", "ocid1.compartment", "--tag", "team"}, &out); status != 2 {
+		t.Errorf("expected status 2 with a malformed tag, got %d", status)
+	}
+
+	tags := tagFlag{}
+	for _, value := range []string{"team=platform", "env=ci=1"} {
+		if err := tags.Set(value); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if expected := (tagFlag{"team": "platform", "env": "ci=1"}); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("bad tags: %v", tags)
+	}
+}
@@ -0,0 +1,203 @@
+//go:generate mapstructure-to-hcl2 -type ZFSRoot,ZFSDataset
+
+package ocisurrogate
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ZFSRoot describes the ZFS pool holding the root filesystem of the
+// surrogate. It is used when surrogate_layout has a partition with the zfs
+// filesystem or when vdev is set.
+type ZFSRoot struct {
+	// PoolName is the name of the pool. Defaults to rpool.
+	PoolName string `mapstructure:"pool_name"`
+	// Vdev is the device the pool is created on. Defaults to the zfs
+	// partition of surrogate_layout.
+	Vdev string `mapstructure:"vdev"`
+	// Ashift of the pool. Defaults to 12.
+	Ashift int `mapstructure:"ashift"`
+	// FeatureFlags sets feature@<name> pool properties, e.g.
+	// `hole_birth = "disabled"`.
+	FeatureFlags map[string]string `mapstructure:"feature_flags"`
+	// Compression of the pool's root dataset. Defaults to lz4.
+	Compression string `mapstructure:"compression"`
+	// RootProperties are the properties of the pool's root dataset,
+	// inherited by every dataset. Defaults to canmount=off, atime=off and
+	// normalization=formD.
+	RootProperties map[string]string `mapstructure:"root_properties"`
+	// Datasets are created in the order they are listed. Defaults to
+	// ROOT (not mounted) and ROOT/default mounted on /.
+	Datasets []ZFSDataset `mapstructure:"dataset"`
+	// SwapSize is the size of the swap zvol, e.g. 4G. No swap zvol is
+	// created when empty.
+	SwapSize string `mapstructure:"swap_size"`
+
+	enabled bool
+}
+
+// ZFSDataset is a dataset of the ZFS root pool.
+type ZFSDataset struct {
+	// Name of the dataset relative to the pool, e.g. ROOT/oel.
+	Name string `mapstructure:"name"`
+	// Properties of the dataset, e.g. mountpoint, canmount or quota.
+	Properties map[string]string `mapstructure:"properties"`
+}
+
+// zfsSwapProperties are the properties of the swap zvol recommended by the
+// ZFS on Linux root filesystem guides.
+var zfsSwapProperties = map[string]string{
+	"compression":           "zle",
+	"logbias":               "throughput",
+	"sync":                  "always",
+	"primarycache":          "metadata",
+	"secondarycache":        "none",
+	"com.sun:auto-snapshot": "false",
+}
+
+func (z *ZFSRoot) Prepare(layout *SurrogateLayout) []error {
+	var errs []error
+
+	z.enabled = z.Vdev != "" || layout.partitionNumber("zfs") != 0
+	if !z.enabled {
+		if len(z.Datasets) > 0 || z.SwapSize != "" {
+			errs = append(errs, fmt.Errorf("zfs_root: either 'vdev' or a surrogate_layout partition with the zfs filesystem must be specified"))
+		}
+		return errs
+	}
+
+	if z.PoolName == "" {
+		z.PoolName = "rpool"
+	}
+	if z.Ashift == 0 {
+		z.Ashift = 12
+	}
+	if z.Ashift < 9 || z.Ashift > 16 {
+		errs = append(errs, fmt.Errorf("zfs_root: ashift must be between 9 and 16, got %d", z.Ashift))
+	}
+	if z.Compression == "" {
+		z.Compression = "lz4"
+	}
+	if z.RootProperties == nil {
+		z.RootProperties = map[string]string{
+			"canmount":      "off",
+			"atime":         "off",
+			"normalization": "formD",
+		}
+	}
+	if len(z.Datasets) == 0 {
+		z.Datasets = []ZFSDataset{
+			{Name: "ROOT", Properties: map[string]string{"canmount": "off", "mountpoint": "none"}},
+			{Name: "ROOT/default", Properties: map[string]string{"canmount": "noauto", "mountpoint": "/"}},
+		}
+	}
+	if z.SwapSize != "" && !partitionSizeRe.MatchString(z.SwapSize) {
+		errs = append(errs, fmt.Errorf("zfs_root: invalid swap_size %q", z.SwapSize))
+	}
+
+	names := map[string]bool{}
+	for _, d := range z.Datasets {
+		if d.Name == "" || strings.HasPrefix(d.Name, "/") || strings.HasSuffix(d.Name, "/") {
+			errs = append(errs, fmt.Errorf("zfs_root: invalid dataset name %q", d.Name))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("zfs_root: dataset %q is listed more than once", d.Name))
+		}
+		if parent := path.Dir(d.Name); parent != "." && !names[parent] {
+			errs = append(errs, fmt.Errorf("zfs_root: dataset %q must be listed after its parent %q", d.Name, parent))
+		}
+		names[d.Name] = true
+	}
+	if z.rootDataset() == "" {
+		errs = append(errs, fmt.Errorf("zfs_root: a dataset with mountpoint=/ must be specified"))
+	}
+
+	return errs
+}
+
+// rootDataset returns the full name of the dataset mounted on /, or an empty
+// string if there is none.
+func (z *ZFSRoot) rootDataset() string {
+	for _, d := range z.Datasets {
+		if d.Properties["mountpoint"] == "/" {
+			return z.PoolName + "/" + d.Name
+		}
+	}
+	return ""
+}
+
+// zpoolCreateCommand returns the command creating the pool on vdev with its
+// datasets mounted under altroot. The pool is kept out of the zpool.cache of
+// the builder instance; the bootloader step writes the cache of the target
+// from within the chroot.
+func (z *ZFSRoot) zpoolCreateCommand(altroot string, vdev string) string {
+	args := []string{
+		"zpool create -f",
+		"-o altroot=" + altroot,
+		fmt.Sprintf("-o ashift=%d", z.Ashift),
+		"-o cachefile=none",
+	}
+	for _, k := range sortedKeys(z.FeatureFlags) {
+		args = append(args, "-o "+shellQuote(fmt.Sprintf("feature@%s=%s", k, z.FeatureFlags[k])))
+	}
+	args = append(args, "-O compression="+shellQuote(z.Compression))
+	for _, k := range sortedKeys(z.RootProperties) {
+		args = append(args, "-O "+shellQuote(k+"="+z.RootProperties[k]))
+	}
+	args = append(args, "-m none", shellQuote(z.PoolName), vdev)
+	return strings.Join(args, " ")
+}
+
+// datasetCommands returns the commands creating the datasets of the pool.
+// Datasets with canmount=noauto mounted on / are mounted right after being
+// created so that their children are mounted on top of them.
+func (z *ZFSRoot) datasetCommands() []string {
+	var commands []string
+	for _, d := range z.Datasets {
+		name := shellQuote(z.PoolName + "/" + d.Name)
+		commands = append(commands, "zfs create "+zfsOptions(d.Properties)+name)
+		if d.Properties["mountpoint"] == "/" && d.Properties["canmount"] == "noauto" {
+			commands = append(commands, "zfs mount "+name)
+		}
+	}
+	return commands
+}
+
+// swapCommands returns the commands creating and formatting the swap zvol,
+// if any.
+func (z *ZFSRoot) swapCommands() []string {
+	if z.SwapSize == "" {
+		return nil
+	}
+	zvol := z.PoolName + "/swap"
+	return []string{
+		fmt.Sprintf("zfs create -V %s -b $(getconf PAGESIZE) %s%s",
+			z.SwapSize, zfsOptions(zfsSwapProperties), shellQuote(zvol)),
+		"udevadm settle",
+		"mkswap " + shellQuote("/dev/zvol/"+zvol),
+	}
+}
+
+// zfsOptions returns properties as zfs create -o options, followed by a
+// space when not empty.
+func zfsOptions(properties map[string]string) string {
+	var options string
+	for _, k := range sortedKeys(properties) {
+		options += "-o " + shellQuote(k+"="+properties[k]) + " "
+	}
+	return options
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package ocisurrogate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testZFSLayout() SurrogateLayout {
+	return SurrogateLayout{
+		Partitions: []SurrogatePartition{
+			{Type: "EF00", Size: "210M", Filesystem: "vfat", MountPoint: "/boot/efi"},
+			{Type: "8300", Size: "1G", Filesystem: "ext4", MountPoint: "/boot"},
+			{Type: "BF01", Filesystem: "zfs"},
+		},
+	}
+}
+
+func TestZFSRootPrepare(t *testing.T) {
+	layout := testZFSLayout()
+	layout.Prepare()
+
+	z := ZFSRoot{}
+	if errs := z.Prepare(&layout); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !z.enabled {
+		t.Fatalf("should be enabled by the zfs partition")
+	}
+	if z.PoolName != "rpool" || z.Ashift != 12 || z.Compression != "lz4" {
+		t.Errorf("bad defaults: %+v", z)
+	}
+	if root := z.rootDataset(); root != "rpool/ROOT/default" {
+		t.Errorf("expected root dataset rpool/ROOT/default, got %s", root)
+	}
+}
+
+func TestZFSRootPrepare_Disabled(t *testing.T) {
+	layout := testSurrogateLayout()
+	layout.Prepare()
+
+	z := ZFSRoot{}
+	if errs := z.Prepare(&layout); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if z.enabled {
+		t.Fatalf("should not be enabled without a zfs partition")
+	}
+
+	z = ZFSRoot{SwapSize: "4G"}
+	if errs := z.Prepare(&layout); len(errs) == 0 {
+		t.Fatalf("expected an error without a zfs partition")
+	}
+}
+
+func TestZFSRootPrepare_Errors(t *testing.T) {
+	cases := map[string]ZFSRoot{
+		"bad ashift":    {Ashift: 20},
+		"bad swap size": {SwapSize: "4 GB"},
+		"no root": {Datasets: []ZFSDataset{
+			{Name: "ROOT", Properties: map[string]string{"mountpoint": "none"}},
+		}},
+		"child before parent": {Datasets: []ZFSDataset{
+			{Name: "ROOT/oel", Properties: map[string]string{"mountpoint": "/"}},
+			{Name: "ROOT"},
+		}},
+		"duplicate": {Datasets: []ZFSDataset{
+			{Name: "ROOT", Properties: map[string]string{"mountpoint": "/"}},
+			{Name: "ROOT"},
+		}},
+	}
+
+	for name, z := range cases {
+		t.Run(name, func(t *testing.T) {
+			layout := testZFSLayout()
+			layout.Prepare()
+			if errs := z.Prepare(&layout); len(errs) == 0 {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestZFSRootCommands(t *testing.T) {
+	layout := testZFSLayout()
+	layout.Prepare()
+
+	z := ZFSRoot{
+		FeatureFlags: map[string]string{"hole_birth": "disabled"},
+		Datasets: []ZFSDataset{
+			{Name: "ROOT", Properties: map[string]string{"canmount": "off", "mountpoint": "none"}},
+			{Name: "ROOT/oel", Properties: map[string]string{"canmount": "noauto", "mountpoint": "/"}},
+			{Name: "var", Properties: map[string]string{"mountpoint": "/var", "quota": "8G"}},
+		},
+		SwapSize: "4G",
+	}
+	if errs := z.Prepare(&layout); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	expected := "zpool create -f -o altroot=/mnt -o ashift=12 -o cachefile=none " +
+		"-o 'feature@hole_birth=disabled' -O compression='lz4' -O 'atime=off' -O 'canmount=off' " +
+		"-O 'normalization=formD' -m none 'rpool' /dev/sdb3"
+	if got := z.zpoolCreateCommand("/mnt", "/dev/sdb3"); got != expected {
+		t.Errorf("bad zpool command:\n got: %s\nwant: %s", got, expected)
+	}
+
+	expectedDatasets := []string{
+		"zfs create -o 'canmount=off' -o 'mountpoint=none' 'rpool/ROOT'",
+		"zfs create -o 'canmount=noauto' -o 'mountpoint=/' 'rpool/ROOT/oel'",
+		"zfs mount 'rpool/ROOT/oel'",
+		"zfs create -o 'mountpoint=/var' -o 'quota=8G' 'rpool/var'",
+	}
+	if got := z.datasetCommands(); !reflect.DeepEqual(got, expectedDatasets) {
+		t.Errorf("bad dataset commands:\n got: %v\nwant: %v", got, expectedDatasets)
+	}
+
+	if got := z.swapCommands(); len(got) != 3 || got[2] != "mkswap '/dev/zvol/rpool/swap'" {
+		t.Errorf("bad swap commands: %v", got)
+	}
+}